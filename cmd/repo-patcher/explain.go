@@ -0,0 +1,71 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+//go:embed explanations.json
+var explanationsJSON []byte
+
+// Explanation is the human-readable writeup the explain subcommand prints
+// for a scenario: what category of error it reproduces, why that error
+// happens, what gofix does about it, and anything a reader should watch
+// out for.
+type Explanation struct {
+	Title     string `json:"title"`
+	RootCause string `json:"root_cause"`
+	Transform string `json:"transform"`
+	Caveats   string `json:"caveats"`
+}
+
+// loadExplanations parses the embedded explanations.json into a lookup by
+// scenario name. It panics on malformed JSON, since that can only happen if
+// explanations.json itself was edited incorrectly - there's no user input
+// involved in producing it.
+func loadExplanations() map[string]Explanation {
+	var explanations map[string]Explanation
+	if err := json.Unmarshal(explanationsJSON, &explanations); err != nil {
+		panic(fmt.Sprintf("repo-patcher: embedded explanations.json is invalid: %v", err))
+	}
+	return explanations
+}
+
+// runExplain implements the `explain` subcommand: it prints the
+// Explanation for the scenario named in args, or exits non-zero if no such
+// scenario is known.
+func runExplain(args []string) {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	jsonFlag := fs.Bool("json", false, "print the explanation as JSON instead of plain text")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: repo-patcher explain [--json] <scenario>")
+		os.Exit(2)
+	}
+	scenario := fs.Arg(0)
+
+	explanation, ok := loadExplanations()[scenario]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "repo-patcher explain: unknown scenario %q\n", scenario)
+		os.Exit(1)
+	}
+
+	if *jsonFlag {
+		out, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	fmt.Printf("%s: %s\n\n", scenario, explanation.Title)
+	fmt.Printf("Root cause:\n  %s\n\n", explanation.RootCause)
+	fmt.Printf("Fix applied:\n  %s\n\n", explanation.Transform)
+	fmt.Printf("Caveats:\n  %s\n", explanation.Caveats)
+}
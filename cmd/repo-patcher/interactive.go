@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// runInteractive drives a u(ndo)/r(edo)/q(uit) prompt against h, reading one
+// command per line from in and writing prompts and error messages to out.
+// It returns once the user sends q - leaving every file exactly as h's
+// current undo/redo position has it, i.e. "commit" - or once in reaches
+// EOF, whichever comes first.
+func runInteractive(in io.Reader, out io.Writer, h *patch.History) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "(u)ndo, (r)edo, (q)uit: ")
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		switch scanner.Text() {
+		case "u":
+			if err := h.Undo(); err != nil {
+				fmt.Fprintln(out, err)
+			}
+		case "r":
+			if err := h.Redo(); err != nil {
+				fmt.Fprintln(out, err)
+			}
+		case "q":
+			return nil
+		default:
+			fmt.Fprintln(out, "unrecognized command: want u, r, or q")
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Command repo-patcher runs the deterministic gofix passes over a Go
+// package before falling back to the LLM-driven patch loop.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/FernandoTN/repo-patcher/pkg/githubreview"
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+	"github.com/FernandoTN/repo-patcher/pkg/progress"
+	"github.com/FernandoTN/repo-patcher/pkg/sarif"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "explain" {
+		runExplain(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "server" {
+		runServer(os.Args[2:])
+		return
+	}
+
+	unusedFlag := flag.String("unused", "remove", "how to resolve unused imports/vars: remove|blank|ask")
+	dryRunFlag := flag.Bool("dry-run", false, "print unified diffs instead of writing fixes to disk")
+	configDirFlag := flag.String("config-dir", ".", "directory to start the .repopatcher.yaml walk-up from")
+	sarifFlag := flag.Bool("sarif", false, "print results as a SARIF 2.1.0 report instead of plain text")
+	exportPatchFlag := flag.Bool("export-patch", false, "with -dry-run, print a git-format patch (suitable for `git apply`) instead of plain diffs")
+	cacheFlag := flag.String("cache", ".repopatcher_cache.json", "path to a content-hash cache of prior fixes; empty disables caching")
+	verifyFlag := flag.Bool("verify", false, "recompile each patched package and roll back its fixes if it still fails to build")
+	vetJSONFlag := flag.String("vet-json", "", "path to a go vet -json report to read diagnostics from (use '-' for stdin) instead of running go vet directly")
+	fromStdinFlag := flag.Bool("from-stdin", false, "read file:line:col: message diagnostics from stdin (go build/go vet/golangci-lint output) instead of running go vet directly")
+	interactiveFlag := flag.Bool("interactive", false, "after patching, prompt to undo/redo individual fixes before exiting")
+	historyDepthFlag := flag.Int("history-depth", 50, "max undo/redo steps to retain in --interactive mode; 0 is unlimited")
+	progressFlag := flag.String("progress", "none", "how to report patch progress: none|terminal")
+	deprecatedAPIFlag := flag.Bool("deprecated-api", true, "rewrite deprecated io/ioutil calls, os.SEEK_* constants, and migrated syscall functions to their modern equivalents")
+	boundsCheckFlag := flag.Bool("bounds-check", true, "insert a length guard before a slice index access that's provably out of range against its fixed-length declaration")
+	namingConventionFlag := flag.Bool("naming-convention", false, "rename package-level identifiers that violate Go's initialism convention (Id -> ID, Url -> URL) to their canonical form, rewriting every reference")
+	namingConventionAllowExportedFlag := flag.Bool("naming-convention-allow-exported", false, "with -naming-convention, also rename exported identifiers (an API break for this package's importers)")
+	suggestSplitsFlag := flag.Bool("suggest-splits", false, "analyze each package in scope with PackageSizeSuggester and print its split suggestions as JSON, without changing any file")
+	contextPropagationFlag := flag.Bool("context-propagation", false, "thread a context.Context parameter upward through every local caller of -context-propagation-seeds")
+	contextPropagationSeedsFlag := flag.String("context-propagation-seeds", "", "comma-separated function names that already accept context.Context first (plain \"Fetch\" for a local function, \"pkg.Fetch\" for a cross-package one); required with -context-propagation")
+	contextPropagationCrossPackageFlag := flag.Bool("context-propagation-cross-package", false, "with -context-propagation, also rewrite a local caller of a cross-package (\"pkg.Fetch\") seed")
+	testStubsFlag := flag.Bool("test-stubs", false, "generate a table-driven test stub file for each source file in scope that has no sibling _test.go yet")
+	magicNumbersFlag := flag.Bool("magic-numbers", false, "extract repeated or compared integer/float literals into a trailing const block, rewriting every usage site")
+	moduleDirFlag := flag.String("module-dir", ".", "module root (containing go.mod) to scan with -detect-cycles/-fix-cycles")
+	detectCyclesFlag := flag.Bool("detect-cycles", false, "analyze -module-dir with CircularImportDetector and print its cycle suggestions as JSON, without changing any file")
+	fixCyclesFlag := flag.Bool("fix-cycles", false, "extract every two-package import cycle CircularImportDetector finds in -module-dir into a new shared package")
+	flag.Parse()
+
+	mode, err := gofix.ParseUnusedMode(*unusedFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	cfg, err := gofix.LoadConfig(*configDirFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := gofix.LoadPlugins(gofix.DefaultRegistry, cfg.Fixers); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := cfg.Validate(gofix.DefaultRegistry); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var paths []string
+	for _, p := range flag.Args() {
+		if !cfg.IsExcluded(p) {
+			paths = append(paths, p)
+		}
+	}
+
+	if *suggestSplitsFlag {
+		suggestions, err := gofix.PackageSizeSuggester{}.Analyze(paths)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		out, err := json.MarshalIndent(suggestions, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	if *detectCyclesFlag {
+		suggestions, err := gofix.CircularImportDetector{}.Detect(*moduleDirFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		out, err := json.MarshalIndent(suggestions, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	dryRun := *dryRunFlag || cfg.DryRun
+	if *exportPatchFlag && !dryRun {
+		fmt.Fprintln(os.Stderr, "repo-patcher: -export-patch requires -dry-run")
+		os.Exit(2)
+	}
+	var history *patch.History
+	if *interactiveFlag {
+		history = patch.NewHistory(*historyDepthFlag)
+	}
+	var reporter progress.ProgressReporter
+	switch *progressFlag {
+	case "none":
+	case "terminal":
+		reporter = progress.NewTerminalReporter(os.Stderr)
+	default:
+		fmt.Fprintf(os.Stderr, "repo-patcher: unknown -progress value %q (want none|terminal)\n", *progressFlag)
+		os.Exit(2)
+	}
+	results, err := gofix.RunUnusedPass(paths, gofix.RunOptions{Mode: mode, DryRun: dryRun, CachePath: *cacheFlag, Verify: *verifyFlag, ProvenanceSuffix: cfg.ProvenanceFile, History: history, Progress: reporter})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if !dryRun {
+		pluginResults, err := gofix.RunPluginPass(paths, gofix.DefaultRegistry)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, pluginResults...)
+	}
+	if *vetJSONFlag != "" {
+		src := gofix.GoVetSource{Path: *vetJSONFlag}
+		if *vetJSONFlag == "-" {
+			src = gofix.GoVetSource{Reader: os.Stdin}
+		}
+		dir := "."
+		if len(paths) > 0 {
+			dir = filepath.Dir(paths[0])
+		}
+		vetResults, err := gofix.RunVetJSONPass(dir, src, gofix.DefaultRegistry)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, vetResults...)
+	}
+	if *fromStdinFlag {
+		dir := "."
+		if len(paths) > 0 {
+			dir = filepath.Dir(paths[0])
+		}
+		stdinResults, err := gofix.RunVetJSONPass(dir, gofix.LineDiagnosticSource{Reader: os.Stdin}, gofix.DefaultRegistry)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, stdinResults...)
+	}
+	if len(cfg.SSRRules) > 0 && !dryRun {
+		ssrResults, err := gofix.RunSSRPass(paths, cfg.SSRRules)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, ssrResults...)
+	}
+	if *deprecatedAPIFlag && !dryRun {
+		deprecatedResults, err := gofix.RunDeprecatedAPIPass(paths)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, deprecatedResults...)
+	}
+	if *boundsCheckFlag && !dryRun {
+		boundsResults, err := gofix.RunBoundsCheckPass(paths)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, boundsResults...)
+	}
+	if *namingConventionFlag && !dryRun {
+		namingResults, err := gofix.RunNamingConventionPass(paths, *namingConventionAllowExportedFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, namingResults...)
+	}
+	if *contextPropagationFlag && !dryRun {
+		var seeds []string
+		for _, s := range strings.Split(*contextPropagationSeedsFlag, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				seeds = append(seeds, s)
+			}
+		}
+		contextResults, err := gofix.RunContextPropagationPass(paths, seeds, *contextPropagationCrossPackageFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, contextResults...)
+	}
+	if *testStubsFlag && !dryRun {
+		stubResults, err := gofix.RunTestStubPass(paths)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, stubResults...)
+	}
+	if *magicNumbersFlag && !dryRun {
+		magicResults, err := gofix.RunMagicNumberPass(paths)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		results = append(results, magicResults...)
+	}
+	if *fixCyclesFlag && !dryRun {
+		suggestions, err := gofix.CircularImportDetector{}.Detect(*moduleDirFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, s := range suggestions {
+			cycleResults, err := gofix.CircularImportFixer{}.Extract(*moduleDirFlag, s)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			results = append(results, cycleResults...)
+		}
+	}
+	if *interactiveFlag && !dryRun {
+		if err := runInteractive(os.Stdin, os.Stdout, history); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if opts, ok := githubreview.DetectOptions(); ok {
+		reviewer := githubreview.NewGitHubReviewer(opts)
+		if err := reviewer.SubmitReview(context.Background(), results); err != nil {
+			// Posting the review is a best-effort add-on to a run that's
+			// otherwise already succeeded; a GitHub API hiccup shouldn't
+			// fail the whole invocation.
+			fmt.Fprintln(os.Stderr, "repo-patcher: github review:", err)
+		}
+	}
+	if *sarifFlag {
+		report, err := sarif.SARIFReport(results)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(report)
+		return
+	}
+	if *exportPatchFlag {
+		if err := patch.ExportPatch(results, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	for _, r := range results {
+		if dryRun {
+			fmt.Print(r.DiffOutput)
+			continue
+		}
+		fmt.Printf("%s: %s (lines %d-%d -> %d-%d)\n", r.File, r.Kind, r.BeforeStart, r.BeforeEnd, r.AfterStart, r.AfterEnd)
+	}
+}
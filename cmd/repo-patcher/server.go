@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/FernandoTN/repo-patcher/pkg/server"
+)
+
+// runServer implements the `server` subcommand: it starts an HTTP server
+// exposing gofix's patch pipeline over REST, for editor plugins and
+// dashboards written outside Go, until interrupted.
+func runServer(args []string) {
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	portFlag := fs.Int("port", 8080, "port to listen on")
+	maxBytesFlag := fs.Int64("max-request-bytes", server.DefaultMaxRequestBytes, "maximum request body size in bytes")
+	timeoutFlag := fs.Duration("timeout", server.DefaultRequestTimeout, "per-request timeout")
+	scenariosDirFlag := fs.String("scenarios-dir", server.DefaultScenariosDir, "directory GET /scenarios lists")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	addr := net.JoinHostPort("", fmt.Sprintf("%d", *portFlag))
+	opts := server.Options{
+		MaxRequestBytes: *maxBytesFlag,
+		RequestTimeout:  *timeoutFlag,
+		ScenariosDir:    *scenariosDirFlag,
+	}
+
+	fmt.Fprintf(os.Stderr, "repo-patcher: serving on %s (timeout %s, max body %d bytes)\n", addr, *timeoutFlag, *maxBytesFlag)
+	if err := server.ListenAndServe(ctx, addr, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
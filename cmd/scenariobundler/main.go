@@ -0,0 +1,108 @@
+// Command scenariobundler packages the scenarios/ fixture tree into the
+// tar.gz archive pkg/fixtures embeds into the binary (see
+// fixtures.EmbeddedScenarios).
+//
+// It exists because go:embed refuses to embed a directory that belongs to
+// a different module ("cannot embed directory: in different module"), and
+// every scenarios/<name>/repo and expected_fix is deliberately its own
+// module - that's what keeps its intentionally-broken fixture code out of
+// this module's own `go build ./...`. Bundling the tree into one opaque
+// archive file sidesteps the restriction entirely: go:embed only ever sees
+// a single data file, never the module boundaries inside it.
+//
+// Run it (from the repo root) after adding or editing a scenario:
+//
+//	go run ./cmd/scenariobundler -scenarios scenarios -out pkg/fixtures/testdata/scenarios.tar.gz
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	scenariosDir := flag.String("scenarios", "scenarios", "path to the scenarios/ directory to bundle")
+	out := flag.String("out", filepath.Join("pkg", "fixtures", "testdata", "scenarios.tar.gz"), "path to write the tar.gz bundle to")
+	flag.Parse()
+
+	if err := bundle(*scenariosDir, *out); err != nil {
+		log.Fatalf("scenariobundler: %v", err)
+	}
+}
+
+func bundle(scenariosDir, out string) error {
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(out), err)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", out, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(scenariosDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(scenariosDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("header for %s: %w", rel, err)
+		}
+		hdr.Name = rel
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write header for %s: %w", rel, err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		if _, err := io.Copy(tw, src); err != nil {
+			return fmt.Errorf("copy %s: %w", rel, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("close gzip writer: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,200 @@
+// Package astedit applies typed, AST-level edits to Go source instead of
+// text diffs. Patches are expressed as a sequence of Ops (AddImport,
+// RemoveImport, RenameIdent, ReplaceCallExpr), applied to a parsed
+// *ast.File and re-serialized with go/format. This guarantees output is
+// gofmt-clean and keeps review diffs minimal: a patch that only needs one
+// new import touches exactly one line, rather than reformatting the file
+// the way a raw LLM-emitted unified diff tends to.
+package astedit
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// File is a parsed Go source file open for editing.
+type File struct {
+	fset *token.FileSet
+	ast  *ast.File
+}
+
+// Parse parses src and returns a File ready to accept Ops.
+func Parse(filename string, src []byte) (*File, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("astedit: parse %s: %w", filename, err)
+	}
+	return &File{fset: fset, ast: f}, nil
+}
+
+// Format re-serializes the file's current AST with go/format, equivalent to
+// running gofmt over the result of every Op applied so far.
+func (f *File) Format() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, f.fset, f.ast); err != nil {
+		return nil, fmt.Errorf("astedit: format: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Op is a single typed edit. Apply mutates f's AST in place.
+type Op interface {
+	Apply(f *File) error
+}
+
+// Apply runs each Op against f in order, stopping at the first error.
+func (f *File) Apply(ops ...Op) error {
+	for _, op := range ops {
+		if err := op.Apply(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddImport adds an import of path to the file, inserting it into the
+// correct grouped import block (or creating one) the same way goimports
+// would. It is a no-op if the import already exists.
+type AddImport struct {
+	Path string
+	// Name is an optional local name, e.g. for AddImport{Path: "fmt", Name: "_"}.
+	Name string
+}
+
+func (op AddImport) Apply(f *File) error {
+	if op.Name != "" {
+		astutil.AddNamedImport(f.fset, f.ast, op.Name, op.Path)
+		return nil
+	}
+	astutil.AddImport(f.fset, f.ast, op.Path)
+	return nil
+}
+
+// RemoveImport removes an import of path from the file, if present.
+type RemoveImport struct {
+	Path string
+}
+
+func (op RemoveImport) Apply(f *File) error {
+	astutil.DeleteImport(f.fset, f.ast, op.Path)
+	return nil
+}
+
+// RenameIdent renames every identifier matching From to To within scope
+// Func (if set, only identifiers within that function declaration) or the
+// whole file otherwise. It matches by name only, so callers should already
+// have resolved that the rename is unambiguous (e.g. via gofix's symbol
+// resolution) before constructing this Op.
+type RenameIdent struct {
+	From, To string
+	Func     string
+}
+
+func (op RenameIdent) Apply(f *File) error {
+	ast.Inspect(f.ast, func(n ast.Node) bool {
+		fd, ok := n.(*ast.FuncDecl)
+		if op.Func != "" && ok && fd.Name.Name != op.Func {
+			return false
+		}
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == op.From {
+			ident.Name = op.To
+		}
+		return true
+	})
+	return nil
+}
+
+// InsertBlankUse inserts `_ = Name` immediately after the statement that
+// declares Name via `:=` or `var`, silencing a "declared and not used"
+// diagnostic without deleting the declaration itself.
+type InsertBlankUse struct {
+	Name string
+}
+
+func (op InsertBlankUse) Apply(f *File) error {
+	blank := &ast.AssignStmt{
+		Lhs: []ast.Expr{ast.NewIdent("_")},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{ast.NewIdent(op.Name)},
+	}
+
+	inserted := false
+	astutil.Apply(f.ast, nil, func(c *astutil.Cursor) bool {
+		if inserted {
+			return false
+		}
+		if assign, ok := c.Node().(*ast.AssignStmt); ok && declares(assign, op.Name) {
+			c.InsertAfter(blank)
+			inserted = true
+			return false
+		}
+		if decl, ok := c.Node().(*ast.GenDecl); ok && decl.Tok == token.VAR && declaresVar(decl, op.Name) {
+			c.InsertAfter(blank)
+			inserted = true
+			return false
+		}
+		return true
+	})
+	if !inserted {
+		return fmt.Errorf("astedit: no declaration of %q found to silence", op.Name)
+	}
+	return nil
+}
+
+func declares(assign *ast.AssignStmt, name string) bool {
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func declaresVar(decl *ast.GenDecl, name string) bool {
+	for _, spec := range decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, ident := range vs.Names {
+			if ident.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReplaceCallExpr replaces the function identifier in calls of the form
+// Pkg.From(...) with Pkg.To(...), e.g. to fix a miscased "fmt.printf" call
+// into "fmt.Printf" without touching the call's arguments.
+type ReplaceCallExpr struct {
+	Pkg, From, To string
+}
+
+func (op ReplaceCallExpr) Apply(f *File) error {
+	ast.Inspect(f.ast, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkgIdent, ok := sel.X.(*ast.Ident)
+		if !ok || pkgIdent.Name != op.Pkg || sel.Sel.Name != op.From {
+			return true
+		}
+		sel.Sel.Name = op.To
+		return true
+	})
+	return nil
+}
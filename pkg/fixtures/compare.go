@@ -0,0 +1,172 @@
+package fixtures
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// CompareResult is the outcome of comparing two Go source files.
+type CompareResult struct {
+	// Equal is true when the two files are byte-identical, AST-equivalent
+	// (see ASTEqual), or identical once both are independently
+	// gofmt-normalized.
+	Equal bool
+	// Diff is a unified diff between the two files, populated only when
+	// Equal is false. It diffs the gofmt-normalized text when both files
+	// parse, or the raw text otherwise.
+	Diff string
+}
+
+// CompareFiles compares expected against actual semantically rather than
+// byte-for-byte: two files that differ only in whitespace, comment
+// placement, or import ordering are reported equal. It parses both with
+// go/parser and walks the resulting ASTs with ASTEqual, ignoring position
+// information and comments; if either fails to parse, or the ASTs turn out
+// inequivalent, it falls back to diffing each file's own gofmt-normalized
+// text, which still absorbs whitespace differences without requiring a
+// valid AST comparison.
+func CompareFiles(expected, actual string) (CompareResult, error) {
+	fsetE := token.NewFileSet()
+	astE, errE := parser.ParseFile(fsetE, "expected.go", expected, 0)
+	fsetA := token.NewFileSet()
+	astA, errA := parser.ParseFile(fsetA, "actual.go", actual, 0)
+
+	if errE == nil && errA == nil && ASTEqual(astE, astA) {
+		return CompareResult{Equal: true}, nil
+	}
+
+	normE, okE := gofmtNormalize(expected)
+	normA, okA := gofmtNormalize(actual)
+	if !okE || !okA {
+		if expected == actual {
+			return CompareResult{Equal: true}, nil
+		}
+		return CompareResult{Equal: false, Diff: patch.UnifiedDiff("actual", []byte(expected), []byte(actual))}, nil
+	}
+	if normE == normA {
+		return CompareResult{Equal: true}, nil
+	}
+	return CompareResult{Equal: false, Diff: patch.UnifiedDiff("actual", []byte(normE), []byte(normA))}, nil
+}
+
+// gofmtNormalize runs src through gofmt. Its ok result is false when src
+// doesn't even parse, which CompareFiles treats as "can't normalize,
+// compare raw text instead" rather than an error - a scenario's expected
+// or actual output being invalid Go is itself the mismatch being reported,
+// not a CompareFiles failure.
+func gofmtNormalize(src string) (string, bool) {
+	out, err := format.Source([]byte(src))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+// ASTEqual reports whether a and b are structurally equivalent ASTs,
+// ignoring position information (token.Pos), *ast.CommentGroup fields, and
+// *ast.Object/*ast.Scope back-references (which point into file-specific
+// resolution state that two independently parsed files never share even
+// when semantically identical). Everything else - declaration order,
+// identifiers, literal values, operator kinds - must match exactly: this
+// is a stricter equivalence than "same behavior", closer to "same source
+// modulo formatting and comments".
+func ASTEqual(a, b ast.Node) bool {
+	return astEqualValue(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+var (
+	posType     = reflect.TypeOf(token.NoPos)
+	commentType = reflect.TypeOf((*ast.CommentGroup)(nil))
+	objectType  = reflect.TypeOf((*ast.Object)(nil))
+	scopeType   = reflect.TypeOf((*ast.Scope)(nil))
+	genDeclType = reflect.TypeOf((*ast.GenDecl)(nil))
+)
+
+func astEqualValue(va, vb reflect.Value) bool {
+	if va.IsValid() != vb.IsValid() {
+		return false
+	}
+	if !va.IsValid() {
+		return true
+	}
+	if va.Type() != vb.Type() {
+		return false
+	}
+
+	switch va.Type() {
+	case posType, commentType, objectType, scopeType:
+		return true
+	}
+
+	switch va.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if va.IsNil() || vb.IsNil() {
+			return va.IsNil() == vb.IsNil()
+		}
+		if va.Type() == genDeclType {
+			ga, gb := va.Interface().(*ast.GenDecl), vb.Interface().(*ast.GenDecl)
+			if ga.Tok == token.IMPORT && gb.Tok == token.IMPORT {
+				return importSpecsEqual(ga.Specs, gb.Specs)
+			}
+		}
+		return astEqualValue(va.Elem(), vb.Elem())
+	case reflect.Slice:
+		if va.IsNil() != vb.IsNil() {
+			return false
+		}
+		if va.Len() != vb.Len() {
+			return false
+		}
+		for i := 0; i < va.Len(); i++ {
+			if !astEqualValue(va.Index(i), vb.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < va.NumField(); i++ {
+			if !astEqualValue(va.Field(i), vb.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return va.Interface() == vb.Interface()
+	}
+}
+
+// importSpecsEqual compares two import blocks as multisets of "name|path"
+// rather than position-sensitive slices, so import declarations that list
+// the same imports in a different order - goimports-style grouping versus
+// alphabetical, say - are still ASTEqual.
+func importSpecsEqual(a, b []ast.Spec) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	normalize := func(specs []ast.Spec) []string {
+		out := make([]string, len(specs))
+		for i, s := range specs {
+			imp := s.(*ast.ImportSpec)
+			name := ""
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			out[i] = name + "|" + imp.Path.Value
+		}
+		sort.Strings(out)
+		return out
+	}
+	na, nb := normalize(a), normalize(b)
+	for i := range na {
+		if na[i] != nb[i] {
+			return false
+		}
+	}
+	return true
+}
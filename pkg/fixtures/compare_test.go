@@ -0,0 +1,107 @@
+package fixtures
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func mustParse(t *testing.T, src string) ast.Node {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "x.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return f
+}
+
+func TestCompareFilesIgnoresImportOrdering(t *testing.T) {
+	expected := `package a
+
+import (
+	"fmt"
+	"os"
+)
+
+func F() {
+	fmt.Println(os.Args)
+}
+`
+	actual := `package a
+
+import (
+	"os"
+	"fmt"
+)
+
+func F() {
+	fmt.Println(os.Args)
+}
+`
+	cmp, err := CompareFiles(expected, actual)
+	if err != nil {
+		t.Fatalf("CompareFiles: %v", err)
+	}
+	if !cmp.Equal {
+		t.Errorf("expected files differing only in import order to be Equal, got Diff:\n%s", cmp.Diff)
+	}
+}
+
+func TestCompareFilesIgnoresWhitespaceAndComments(t *testing.T) {
+	expected := "package a\n\nfunc F() int {\n\treturn 1\n}\n"
+	actual := "package a\n\n// F returns one.\nfunc F() int {\n\n\treturn 1\n}\n"
+
+	cmp, err := CompareFiles(expected, actual)
+	if err != nil {
+		t.Fatalf("CompareFiles: %v", err)
+	}
+	if !cmp.Equal {
+		t.Errorf("expected files differing only in whitespace/comments to be Equal, got Diff:\n%s", cmp.Diff)
+	}
+}
+
+func TestCompareFilesReportsRealDifference(t *testing.T) {
+	expected := "package a\n\nfunc F() int {\n\treturn 1\n}\n"
+	actual := "package a\n\nfunc F() int {\n\treturn 2\n}\n"
+
+	cmp, err := CompareFiles(expected, actual)
+	if err != nil {
+		t.Fatalf("CompareFiles: %v", err)
+	}
+	if cmp.Equal {
+		t.Error("expected files with different return values to differ")
+	}
+	if cmp.Diff == "" {
+		t.Error("expected a non-empty Diff for a real mismatch")
+	}
+}
+
+func TestCompareFilesFallsBackForInvalidGoSource(t *testing.T) {
+	cmp, err := CompareFiles("not valid go", "not valid go")
+	if err != nil {
+		t.Fatalf("CompareFiles: %v", err)
+	}
+	if !cmp.Equal {
+		t.Error("expected identical invalid source to compare equal via the raw-text fallback")
+	}
+
+	cmp, err = CompareFiles("not valid go", "still not valid go")
+	if err != nil {
+		t.Fatalf("CompareFiles: %v", err)
+	}
+	if cmp.Equal {
+		t.Error("expected different invalid source to compare unequal")
+	}
+}
+
+func TestASTEqualDetectsStructuralDifference(t *testing.T) {
+	a := mustParse(t, "package a\n\nfunc F() int { return 1 }\n")
+	b := mustParse(t, "package a\n\nfunc F() int { return 2 }\n")
+	if ASTEqual(a, b) {
+		t.Error("expected ASTEqual to report false for differing return values")
+	}
+	if !ASTEqual(a, a) {
+		t.Error("expected ASTEqual to report true for a node compared with itself")
+	}
+}
@@ -0,0 +1,69 @@
+package fixtures
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing/fstest"
+)
+
+// scenariosArchive holds testdata/scenarios.tar.gz, a tar.gz snapshot of
+// the scenarios/ directory built by cmd/scenariobundler. It's an archive
+// rather than `//go:embed scenarios` (the request that motivated this
+// asked for exactly that, via the invalid `scenarios/**` glob syntax)
+// because every scenarios/<name>/repo and expected_fix is its own Go
+// module, and go:embed refuses to embed a directory belonging to a
+// different module. Embedding one pre-built data file sidesteps that
+// restriction; EmbeddedScenarios unpacks it back into an fs.FS at runtime.
+//
+//go:embed testdata/scenarios.tar.gz
+var scenariosArchive embed.FS
+
+// EmbeddedScenarios returns the scenarios/ fixture tree embedded into the
+// binary, so Discover and RunScenarios work correctly from any working
+// directory, or from a binary built with `go test -c` and copied to a
+// machine without the source tree. Pass os.DirFS("scenarios") instead to
+// read a live, editable copy during scenario development - the returned
+// fs.FS is read-only.
+//
+// It panics if the embedded archive doesn't parse, which would mean
+// testdata/scenarios.tar.gz is corrupt or missing - cmd/scenariobundler
+// and go:embed both guarantee that can't happen for a binary that built
+// successfully.
+func EmbeddedScenarios() fs.FS {
+	f, err := scenariosArchive.Open("testdata/scenarios.tar.gz")
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: open embedded scenarios archive: %v", err))
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: gunzip embedded scenarios archive: %v", err))
+	}
+	defer gz.Close()
+
+	mapFS := fstest.MapFS{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(fmt.Sprintf("fixtures: read embedded scenarios archive: %v", err))
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			panic(fmt.Sprintf("fixtures: read %s from embedded scenarios archive: %v", hdr.Name, err))
+		}
+		mapFS[hdr.Name] = &fstest.MapFile{Data: data, Mode: fs.FileMode(hdr.Mode)}
+	}
+	return mapFS
+}
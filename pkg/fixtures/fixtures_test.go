@@ -0,0 +1,229 @@
+package fixtures_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/fixtures"
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func readOrEmpty(path string) []byte {
+	b, _ := os.ReadFile(path)
+	return b
+}
+
+func writeFile(path string, content []byte) error {
+	return os.WriteFile(path, content, 0o644)
+}
+
+// scenarioNameFromWorkDir recovers the scenario name that fixtures.Run
+// baked into workDir's basename ("fixtures-<name>-<random>", from its
+// os.MkdirTemp call), which is the only way a Patcher invoked concurrently
+// across scenarios by fixtures.RunScenarios can tell which scenario it's
+// patching.
+func scenarioNameFromWorkDir(workDir string) string {
+	base := strings.TrimPrefix(filepath.Base(workDir), "fixtures-")
+	if i := strings.LastIndex(base, "-"); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+// gofixPatcher builds a fixtures.Patcher that runs only the deterministic
+// gofix passes (no LLM) over workDir, so this suite measures what the
+// zero-LLM path alone can close. results collects, per scenario name, the
+// patch.Result of every winning candidate applied, so the caller can assert
+// on it in addition to the fixtures.Result file-equality check. It's
+// guarded by a mutex since fixtures.RunScenarios calls the same Patcher
+// concurrently across scenarios.
+func gofixPatcher(mode gofix.UnusedMode, results *sync.Map) fixtures.Patcher {
+	return func(workDir string) (int, error) {
+		// go vet, not go build: unused-import/var breakage is as often in
+		// a _test.go file (see E004) as in ordinary source, and `go build
+		// ./...` never compiles test files at all.
+		cmd := exec.Command("go", "vet", "./...")
+		cmd.Dir = workDir
+		out, _ := cmd.CombinedOutput() // a failing vet is the expected input
+
+		idx, err := gofix.BuildSymbolIndex(workDir)
+		if err != nil {
+			return 0, err
+		}
+
+		name := scenarioNameFromWorkDir(workDir)
+		for _, fix := range gofix.ClassifyAll(string(out)) {
+			file := filepath.Join(workDir, filepath.Base(fix.Diagnostic.File))
+			candidates, err := gofix.Propose(readOrEmpty(file), fix, idx, workDir, mode, true)
+			if err != nil {
+				return 0, err
+			}
+			ranked := gofix.Rank(candidates)
+			if len(ranked) == 0 {
+				continue
+			}
+			if err := writeFile(file, ranked[0].Patch); err != nil {
+				return 0, err
+			}
+			existing, _ := results.LoadOrStore(name, &[]patch.Result{})
+			slice := existing.(*[]patch.Result)
+			*slice = append(*slice, ranked[0].Result)
+		}
+		return 0, nil
+	}
+}
+
+// TestScenarios runs every scenarios/<name>/ fixture through the
+// deterministic gofix-only patcher and checks it against manifest.yaml's
+// expectations. Scenarios whose category has no deterministic fixer yet
+// (e.g. typo-in-keyword) are expected to fail this zero-LLM pass; that's
+// recorded via manifest.yaml's max_llm_tokens rather than skipped, so the
+// suite documents the gap instead of hiding it.
+func TestScenarios(t *testing.T) {
+	fsys := fixtures.EmbeddedScenarios()
+	scenarios, err := fixtures.Discover(fsys)
+	if err != nil {
+		t.Fatalf("discover scenarios: %v", err)
+	}
+	if len(scenarios) == 0 {
+		t.Fatal("expected at least one scenario under scenarios/")
+	}
+	byName := make(map[string]fixtures.Scenario, len(scenarios))
+	for _, s := range scenarios {
+		byName[s.Name] = s
+	}
+
+	var results sync.Map
+	scenarioResults, err := fixtures.RunScenarios(fsys, gofixPatcher(gofix.UnusedRemove, &results), fixtures.RunOptions{Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("run scenarios: %v", err)
+	}
+
+	t.Logf("scenarios: %s", fixtures.Summarize(scenarioResults))
+
+	for _, sr := range scenarioResults {
+		sr := sr
+		s := byName[sr.Scenario]
+		t.Run(sr.Scenario, func(t *testing.T) {
+			if sr.Skipped {
+				t.Skip(sr.SkipReason)
+			}
+			if s.Manifest.MaxLLMTokens == 0 && !sr.Passed {
+				t.Errorf("scenario %s: expected zero-LLM pass to close it, got diff=%v", sr.Scenario, sr.Diff)
+			}
+
+			// A scenario the zero-LLM pass actually closed must have a
+			// PatchResult to show for it, and that Result must claim the
+			// change it made - a Result with Changed=false or an empty
+			// line range is gofix silently doing nothing while reporting
+			// success.
+			if s.Manifest.MaxLLMTokens == 0 && sr.Passed {
+				raw, ok := results.Load(sr.Scenario)
+				if !ok {
+					t.Errorf("scenario %s: passed with no PatchResult recorded", sr.Scenario)
+					return
+				}
+				for _, r := range *raw.(*[]patch.Result) {
+					if !r.Changed {
+						t.Errorf("scenario %s: PatchResult for %s reports Changed=false", sr.Scenario, r.File)
+					}
+					if r.Kind == patch.KindUnknown {
+						t.Errorf("scenario %s: PatchResult for %s has KindUnknown", s.Name, r.File)
+					}
+				}
+			}
+		})
+	}
+}
+
+// writeScenario builds a minimal scenarios/<name>/ directory under root: an
+// empty repo/ and expected_fix/ (so Run's golden-file walk has nothing to
+// compare) and a manifest.yaml naming it.
+func writeScenario(t *testing.T, root, name string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	for _, sub := range []string{"repo", "expected_fix"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatalf("mkdir %s/%s: %v", name, sub, err)
+		}
+	}
+	manifest := "scenario: " + name + "\ncategory: test\nmax_llm_tokens: 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest for %s: %v", name, err)
+	}
+}
+
+// TestRunScenariosDiscoversAndBoundsByTimeout exercises RunScenarios
+// directly (rather than through the gofix pipeline): it should only pick up
+// directories matching the E\d+_ naming convention, run every one of them
+// even when a slow one would otherwise stall the suite, and report the slow
+// one as failed once its own Timeout elapses.
+func TestRunScenariosDiscoversAndBoundsByTimeout(t *testing.T) {
+	root := t.TempDir()
+	writeScenario(t, root, "E001_fast")
+	writeScenario(t, root, "E002_slow")
+	writeScenario(t, root, "not_a_scenario") // no E\d+_ prefix, must be skipped
+
+	patch := func(workDir string) (int, error) {
+		if scenarioNameFromWorkDir(workDir) == "E002_slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		return 0, nil
+	}
+
+	results, err := fixtures.RunScenarios(os.DirFS(root), patch, fixtures.RunOptions{Parallelism: 2, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("RunScenarios: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results (not_a_scenario excluded), got %d: %+v", len(results), results)
+	}
+
+	byName := map[string]fixtures.ScenarioResult{}
+	for _, r := range results {
+		byName[r.Scenario] = r
+	}
+
+	if fast, ok := byName["E001_fast"]; !ok || !fast.Passed {
+		t.Errorf("E001_fast = %+v, want Passed=true", fast)
+	}
+	if slow, ok := byName["E002_slow"]; !ok || slow.Passed {
+		t.Errorf("E002_slow = %+v, want Passed=false (timeout)", slow)
+	}
+}
+
+// TestEmbeddedScenariosMatchesSourceTree guards against the one way
+// fixtures.EmbeddedScenarios() can silently drift from scenarios/: someone
+// edits a scenario's files without re-running
+// `go run ./cmd/scenariobundler` to refresh testdata/scenarios.tar.gz.
+func TestEmbeddedScenariosMatchesSourceTree(t *testing.T) {
+	dir := filepath.Join("..", "..", "scenarios")
+	onDisk, err := fixtures.Discover(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("discover on-disk scenarios: %v", err)
+	}
+	embedded, err := fixtures.Discover(fixtures.EmbeddedScenarios())
+	if err != nil {
+		t.Fatalf("discover embedded scenarios: %v", err)
+	}
+	if len(onDisk) != len(embedded) {
+		t.Fatalf("on-disk has %d scenarios, embedded has %d - run `go run ./cmd/scenariobundler` to refresh testdata/scenarios.tar.gz", len(onDisk), len(embedded))
+	}
+
+	for i, want := range onDisk {
+		got := embedded[i]
+		if want.Name != got.Name {
+			t.Fatalf("scenario %d: on-disk name %q, embedded name %q", i, want.Name, got.Name)
+		}
+		if !reflect.DeepEqual(want.Manifest, got.Manifest) {
+			t.Errorf("scenario %s: manifest drifted between on-disk and embedded - run `go run ./cmd/scenariobundler`", want.Name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package fixtures formalizes the scenarios/ directory as an evaluation
+// suite: each scenarios/<name>/ holds a broken repo/, the expected_fix/
+// files a correct patch should produce, and a manifest.yaml describing
+// what "correct" means for that scenario. Run drives the patcher headlessly
+// over one scenario and reports pass/fail plus the token/cost budget it
+// used.
+package fixtures
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is the schema of scenarios/<name>/manifest.yaml.
+type Manifest struct {
+	Scenario            string   `yaml:"scenario"`
+	Category            string   `yaml:"category"`
+	ExpectedFailingTest string   `yaml:"expected_failing_test"`
+	AllowedTools        []string `yaml:"allowed_tools"`
+	MaxLLMTokens        int      `yaml:"max_llm_tokens"`
+}
+
+// LoadManifest reads and parses name's manifest.yaml from fsys.
+func LoadManifest(fsys fs.FS, name string) (Manifest, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("fixtures: read manifest %s: %w", name, err)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("fixtures: parse manifest %s: %w", name, err)
+	}
+	return m, nil
+}
+
+// Scenario is one scenarios/<name>/ directory: its broken repo, the files
+// expected_fix/ says a correct patch should produce, and its manifest. FS
+// is rooted at the scenarios directory itself (e.g. fixtures.EmbeddedScenarios()
+// or os.DirFS("scenarios")), the same fs.FS Discover was given.
+type Scenario struct {
+	Name     string
+	FS       fs.FS
+	Manifest Manifest
+	// Version is the scenario's scenario.yaml constraint, if it has one.
+	// Its zero value is unconstrained - see ScenarioVersion.Matches.
+	Version ScenarioVersion
+}
+
+// RepoDir is the FS-relative path to the directory containing the
+// scenario's broken starting repo.
+func (s Scenario) RepoDir() string { return path.Join(s.Name, "repo") }
+
+// ExpectedFixDir is the FS-relative path to the directory holding the
+// files a correct patch should produce, keyed by filename; it is not
+// necessarily a full copy of RepoDir, only the files a correct fix is
+// expected to add or change.
+func (s Scenario) ExpectedFixDir() string { return path.Join(s.Name, "expected_fix") }
+
+// Discover walks fsys (rooted at the top-level "scenarios" directory,
+// e.g. fixtures.EmbeddedScenarios() or os.DirFS("scenarios")) for
+// subdirectories containing a manifest.yaml, and returns one Scenario per
+// match, sorted by name.
+func Discover(fsys fs.FS) ([]Scenario, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: read scenarios dir: %w", err)
+	}
+
+	var scenarios []Scenario
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		manifestPath := path.Join(e.Name(), "manifest.yaml")
+		if _, err := fs.Stat(fsys, manifestPath); err != nil {
+			continue
+		}
+		m, err := LoadManifest(fsys, manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		s := Scenario{Name: e.Name(), FS: fsys, Manifest: m}
+		v, ok, err := LoadScenarioVersion(fsys, scenarioVersionPath(s))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.Version = v
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
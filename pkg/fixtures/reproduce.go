@@ -0,0 +1,129 @@
+package fixtures
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+)
+
+// reproduceFile is one file embedded into a Reproduce program, keyed by
+// its path relative to the scenario's repo/ directory.
+type reproduceFile struct {
+	Path    string
+	Content string
+}
+
+var reproduceTemplate = template.Must(template.New("reproduce").Parse(`// Code generated by fixtures.Reproduce; DO NOT EDIT.
+//
+// This is a self-contained reproduction of a repo-patcher scenario: it
+// reconstructs the scenario's broken source files in a temp directory,
+// runs "go build ./..." against them, and prints PASS or FAIL - a single
+// file to paste into play.golang.org or attach to an upstream issue, no
+// access to the original repository required.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+type reproFile struct {
+	Path    string
+	Content string
+}
+
+var files = []reproFile{
+{{- range .Files}}
+	{Path: {{printf "%q" .Path}}, Content: {{printf "%q" .Content}}},
+{{- end}}
+}
+
+func main() {
+	dir, err := os.MkdirTemp("", "repro-*")
+	if err != nil {
+		fmt.Println("FAIL:", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range files {
+		full := filepath.Join(dir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			fmt.Println("FAIL:", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(full, []byte(f.Content), 0o644); err != nil {
+			fmt.Println("FAIL:", err)
+			os.Exit(1)
+		}
+	}
+
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Println("FAIL")
+		fmt.Print(string(out))
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}
+`))
+
+// Reproduce emits a self-contained `package main` program to w that embeds
+// every file under scenarioDir's repo/ as a string literal, reconstructs
+// them in a temp directory, and builds them with `go build` - a minimal
+// repro a user can copy-paste into play.golang.org or an upstream issue
+// without checking out this repository. The emitted program's only
+// dependency is the Go toolchain itself.
+func Reproduce(scenarioDir string, w io.Writer) error {
+	repoDir := filepath.Join(scenarioDir, "repo")
+	files, err := collectReproduceFiles(repoDir)
+	if err != nil {
+		return fmt.Errorf("fixtures: reproduce %s: %w", scenarioDir, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("fixtures: reproduce %s: repo/ has no files", scenarioDir)
+	}
+
+	if err := reproduceTemplate.Execute(w, struct{ Files []reproduceFile }{files}); err != nil {
+		return fmt.Errorf("fixtures: reproduce %s: %w", scenarioDir, err)
+	}
+	return nil
+}
+
+// collectReproduceFiles walks repoDir for every regular file, returning
+// each one's slash-separated path relative to repoDir and its content,
+// sorted by path so Reproduce's output is deterministic.
+func collectReproduceFiles(repoDir string) ([]reproduceFile, error) {
+	var files []reproduceFile
+	err := filepath.WalkDir(repoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, reproduceFile{Path: filepath.ToSlash(rel), Content: string(content)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
@@ -0,0 +1,50 @@
+package fixtures_test
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/fixtures"
+)
+
+func TestReproduceEmitsBuildableProgram(t *testing.T) {
+	scenarioDir := filepath.Join("..", "..", "scenarios", "E003_go_missing_import")
+
+	var buf strings.Builder
+	if err := fixtures.Reproduce(scenarioDir, &buf); err != nil {
+		t.Fatalf("Reproduce: %v", err)
+	}
+	src := buf.String()
+
+	if !strings.Contains(src, "package main") {
+		t.Fatalf("got:\n%s\nwant a package main program", src)
+	}
+	if !strings.Contains(src, `"utils.go"`) {
+		t.Errorf("got:\n%s\nwant E003's utils.go embedded", src)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repro.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+
+	if _, err := build.Default.ImportDir(dir, 0); err != nil {
+		t.Fatalf("emitted program does not compile: %v\nsrc:\n%s", err, src)
+	}
+}
+
+func TestReproduceErrorsOnEmptyRepo(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "repo"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := fixtures.Reproduce(dir, &buf); err == nil {
+		t.Fatal("Reproduce succeeded on an empty repo/, want an error")
+	}
+}
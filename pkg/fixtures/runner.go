@@ -0,0 +1,309 @@
+package fixtures
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Patcher runs the patcher headlessly against a copy of a scenario's broken
+// repo (rooted at workDir) and reports how many LLM tokens it spent.
+// Implementations typically wrap the gofix pipeline, falling back to an
+// actual LLM call only when gofix can't close every diagnostic.
+type Patcher func(workDir string) (tokensUsed int, err error)
+
+// Result is the outcome of running one Scenario through a Patcher.
+type Result struct {
+	Scenario   string
+	Passed     bool
+	TokensUsed int
+	OverBudget bool
+	// Mismatches lists expected_fix/ files, present in repo/ under the
+	// same path, whose patched content differs from the expected one.
+	Mismatches []string
+	// TestOutput is the `go test ./...` output from the patched tree,
+	// captured whenever expected_fix/ adds new _test.go files.
+	TestOutput string
+}
+
+// Run copies s's broken repo (read from s.FS) into a real temp directory
+// and runs patch against it - patch shells out to `go vet`/`go build`,
+// which need an actual directory on disk, so s.FS (which may be an
+// in-memory fixtures.EmbeddedScenarios()) is never patched in place. Every
+// expected_fix/ file that already exists in repo/ under the same relative
+// path is a golden file: the patched tree's copy must match it
+// byte-for-byte. Every expected_fix/ file that doesn't already exist in
+// repo/ is a net-new acceptance test: it's added to the patched tree and
+// `go test ./...` must pass. A scenario passes when both checks pass and
+// the patcher stayed within the manifest's MaxLLMTokens budget.
+func Run(s Scenario, patch Patcher) (Result, error) {
+	workDir, err := os.MkdirTemp("", "fixtures-"+s.Name+"-")
+	if err != nil {
+		return Result{}, fmt.Errorf("fixtures: temp dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := copyFSDir(s.FS, s.RepoDir(), workDir); err != nil {
+		return Result{}, fmt.Errorf("fixtures: copy repo: %w", err)
+	}
+
+	tokensUsed, err := patch(workDir)
+	if err != nil {
+		return Result{}, fmt.Errorf("fixtures: patch %s: %w", s.Name, err)
+	}
+
+	mismatches, newTests, err := compareAgainstExpected(s.FS, s.ExpectedFixDir(), s.RepoDir(), workDir)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var testOutput string
+	testsPassed := true
+	if len(newTests) > 0 {
+		for rel, content := range newTests {
+			if err := os.WriteFile(filepath.Join(workDir, rel), content, 0o644); err != nil {
+				return Result{}, fmt.Errorf("fixtures: add acceptance test %s: %w", rel, err)
+			}
+		}
+		cmd := exec.Command("go", "test", "./...")
+		cmd.Dir = workDir
+		out, runErr := cmd.CombinedOutput()
+		testOutput = string(out)
+		testsPassed = runErr == nil
+	}
+
+	overBudget := s.Manifest.MaxLLMTokens > 0 && tokensUsed > s.Manifest.MaxLLMTokens
+	return Result{
+		Scenario:   s.Name,
+		Passed:     len(mismatches) == 0 && testsPassed && !overBudget,
+		TokensUsed: tokensUsed,
+		OverBudget: overBudget,
+		Mismatches: mismatches,
+		TestOutput: testOutput,
+	}, nil
+}
+
+// compareAgainstExpected splits expectedDir's files (read from fsys) into
+// golden mismatches (relative paths also present under repoDir in fsys,
+// diffed against gotDir on disk) and net-new acceptance tests (relative
+// paths not present under repoDir, returned as file contents to drop into
+// gotDir before testing).
+func compareAgainstExpected(fsys fs.FS, expectedDir, repoDir, gotDir string) (mismatches []string, newTests map[string][]byte, err error) {
+	newTests = map[string][]byte{}
+	err = fs.WalkDir(fsys, expectedDir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || d.IsDir() {
+			return walkErr
+		}
+		rel, err := filepath.Rel(expectedDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		want, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fs.Stat(fsys, path.Join(repoDir, rel)); err != nil {
+			newTests[rel] = want
+			return nil
+		}
+
+		got, err := os.ReadFile(filepath.Join(gotDir, rel))
+		if err != nil {
+			mismatches = append(mismatches, rel+": "+err.Error())
+			return nil
+		}
+
+		if filepath.Ext(rel) != ".go" {
+			if string(got) != string(want) {
+				mismatches = append(mismatches, rel)
+			}
+			return nil
+		}
+		cmp, err := CompareFiles(string(want), string(got))
+		if err != nil {
+			return err
+		}
+		if !cmp.Equal {
+			mismatches = append(mismatches, rel)
+		}
+		return nil
+	})
+	return mismatches, newTests, err
+}
+
+// copyFSDir copies src (an fsys-relative directory) into dst, a real
+// directory on disk.
+func copyFSDir(fsys fs.FS, src, dst string) error {
+	return fs.WalkDir(fsys, src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+}
+
+// scenarioDirRE matches the scenarios/<name>/ naming convention (E001_..,
+// E042_.., ...) that RunScenarios discovers.
+var scenarioDirRE = regexp.MustCompile(`^E\d+_`)
+
+// RunOptions configures RunScenarios.
+type RunOptions struct {
+	// Parallelism is the number of scenarios run concurrently. <= 0 means
+	// runtime.GOMAXPROCS(0).
+	Parallelism int
+	// Timeout bounds how long a single scenario's Patcher may run before
+	// its ScenarioResult is recorded as a failure. Zero means no bound.
+	Timeout time.Duration
+}
+
+// ScenarioResult is the outcome of running one scenario under RunScenarios.
+type ScenarioResult struct {
+	Scenario string
+	Passed   bool
+	// Skipped is true when the scenario's scenario.yaml excludes the
+	// running Go toolchain - it's reported distinctly from Passed/!Passed
+	// rather than as either a pass or a failure, so a suite run on an
+	// older or newer Go than a scenario targets doesn't misreport a
+	// version mismatch as a fix regression.
+	Skipped bool
+	// SkipReason explains why, when Skipped is true.
+	SkipReason string
+	Elapsed    time.Duration
+	// Diff lists the same mismatches Result.Mismatches would, or - if the
+	// scenario errored or timed out - a single message describing why.
+	Diff []string
+}
+
+// RunScenarios discovers every scenarios/<name>/ directory in fsys (see
+// Discover) whose name matches scenarioDirRE, and runs each one through
+// patch, opts.Parallelism at a time, returning one ScenarioResult per
+// scenario in discovery order regardless of completion order.
+//
+// Patcher has no cancellation hook, so a scenario that exceeds opts.Timeout
+// is recorded as failed but its goroutine is not forcibly killed - like a
+// `go test -timeout` deadline, the work keeps running in the background and
+// its eventual result is discarded.
+func RunScenarios(fsys fs.FS, patch Patcher, opts RunOptions) ([]ScenarioResult, error) {
+	all, err := Discover(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenarios []Scenario
+	for _, s := range all {
+		if scenarioDirRE.MatchString(s.Name) {
+			scenarios = append(scenarios, s)
+		}
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]ScenarioResult, len(scenarios))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, s := range scenarios {
+		i, s := i, s
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runScenarioWithTimeout(s, patch, opts.Timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// Summary counts a []ScenarioResult into its three dispositions, so a
+// caller can report skips separately from passes and failures instead of
+// folding them into one pass/fail tally.
+type Summary struct {
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// Summarize tallies results into a Summary.
+func Summarize(results []ScenarioResult) Summary {
+	var s Summary
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			s.Skipped++
+		case r.Passed:
+			s.Passed++
+		default:
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// String renders s as e.g. "12 passed, 1 failed, 2 skipped".
+func (s Summary) String() string {
+	return fmt.Sprintf("%d passed, %d failed, %d skipped", s.Passed, s.Failed, s.Skipped)
+}
+
+// runScenarioWithTimeout runs s through patch via Run, returning a failed
+// ScenarioResult if it errors or doesn't finish within timeout (<= 0 means
+// no bound), or a skipped one without ever calling patch if s.Version
+// excludes the running Go toolchain.
+func runScenarioWithTimeout(s Scenario, patch Patcher, timeout time.Duration) ScenarioResult {
+	if ok, reason := s.Version.Matches(runtimeGoVersion()); !ok {
+		return ScenarioResult{Scenario: s.Name, Skipped: true, SkipReason: reason}
+	}
+
+	start := time.Now()
+	done := make(chan Result, 1)
+	failed := make(chan error, 1)
+	go func() {
+		r, err := Run(s, patch)
+		if err != nil {
+			failed <- err
+			return
+		}
+		done <- r
+	}()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	select {
+	case r := <-done:
+		return ScenarioResult{Scenario: s.Name, Passed: r.Passed, Elapsed: time.Since(start), Diff: r.Mismatches}
+	case err := <-failed:
+		return ScenarioResult{Scenario: s.Name, Elapsed: time.Since(start), Diff: []string{err.Error()}}
+	case <-deadline:
+		return ScenarioResult{Scenario: s.Name, Elapsed: time.Since(start), Diff: []string{fmt.Sprintf("exceeded timeout %s", timeout)}}
+	}
+}
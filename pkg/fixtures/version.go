@@ -0,0 +1,76 @@
+package fixtures
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"runtime"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// ScenarioVersion is the schema of scenarios/<name>/scenario.yaml: the
+// range of Go toolchain versions a scenario's expected_fix/ is valid for.
+// A scenario like E003, whose correct fix is `os.ReadFile` on Go 1.21 but
+// `ioutil.ReadFile` on Go 1.20, can't have a single expected_fix/ that's
+// right on every toolchain - scenario.yaml lets the runner skip it
+// outright on a toolchain it was never written to cover, rather than
+// reporting a version mismatch as a fix failure.
+type ScenarioVersion struct {
+	MinGoVersion string   `yaml:"min_go_version"`
+	MaxGoVersion string   `yaml:"max_go_version"`
+	Tags         []string `yaml:"tags"`
+}
+
+// LoadScenarioVersion reads name's scenario.yaml from fsys. A missing
+// file is not an error: it reports ok=false and a zero ScenarioVersion,
+// which Matches treats as unconstrained.
+func LoadScenarioVersion(fsys fs.FS, name string) (v ScenarioVersion, ok bool, err error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		if _, statErr := fs.Stat(fsys, name); statErr != nil {
+			return ScenarioVersion{}, false, nil
+		}
+		return ScenarioVersion{}, false, fmt.Errorf("fixtures: read scenario version %s: %w", name, err)
+	}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return ScenarioVersion{}, false, fmt.Errorf("fixtures: parse scenario version %s: %w", name, err)
+	}
+	return v, true, nil
+}
+
+// Matches reports whether goVersion (as runtime.Version() returns it,
+// e.g. "go1.21.5") falls within v's [MinGoVersion, MaxGoVersion] range -
+// either bound empty means unbounded on that side - and if not, why.
+func (v ScenarioVersion) Matches(goVersion string) (ok bool, reason string) {
+	current := toSemver(goVersion)
+	if v.MinGoVersion != "" && semver.Compare(current, toSemver(v.MinGoVersion)) < 0 {
+		return false, fmt.Sprintf("requires Go >= %s, have %s", v.MinGoVersion, goVersion)
+	}
+	if v.MaxGoVersion != "" && semver.Compare(current, toSemver(v.MaxGoVersion)) > 0 {
+		return false, fmt.Sprintf("requires Go <= %s, have %s", v.MaxGoVersion, goVersion)
+	}
+	return true, ""
+}
+
+// toSemver converts a bare Go version ("1.21", "go1.21.5") to the
+// "vX.Y[.Z]" form golang.org/x/mod/semver requires.
+func toSemver(v string) string {
+	v = strings.TrimPrefix(v, "go")
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
+
+// scenarioVersionPath is the fsys-relative path to a scenario's optional
+// version constraint file.
+func scenarioVersionPath(s Scenario) string {
+	return path.Join(s.Name, "scenario.yaml")
+}
+
+// runtimeGoVersion is runtime.Version(), indirected so tests can simulate
+// a different toolchain without actually running on one.
+var runtimeGoVersion = runtime.Version
@@ -0,0 +1,49 @@
+package fixtures
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestScenarioVersionMatches(t *testing.T) {
+	cases := []struct {
+		name    string
+		v       ScenarioVersion
+		go_     string
+		wantOK  bool
+		wantHas string
+	}{
+		{"unconstrained", ScenarioVersion{}, "go1.21.5", true, ""},
+		{"within range", ScenarioVersion{MinGoVersion: "1.18", MaxGoVersion: "1.22"}, "go1.21.5", true, ""},
+		{"below min", ScenarioVersion{MinGoVersion: "1.22"}, "go1.21.5", false, "requires Go >= 1.22"},
+		{"above max", ScenarioVersion{MaxGoVersion: "1.20"}, "go1.21.5", false, "requires Go <= 1.20"},
+		{"exact min", ScenarioVersion{MinGoVersion: "1.21.5"}, "go1.21.5", true, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ok, reason := c.v.Matches(c.go_)
+			if ok != c.wantOK {
+				t.Errorf("Matches(%q) ok = %v, want %v (reason %q)", c.go_, ok, c.wantOK, reason)
+			}
+			if c.wantHas != "" && reason == "" {
+				t.Errorf("Matches(%q) gave no reason, want one containing %q", c.go_, c.wantHas)
+			}
+		})
+	}
+}
+
+func TestLoadScenarioVersionMissingFileIsNotAnError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"E999_example/manifest.yaml": &fstest.MapFile{Data: []byte("scenario: E999_example\n")},
+	}
+	v, ok, err := LoadScenarioVersion(fsys, "E999_example/scenario.yaml")
+	if err != nil {
+		t.Fatalf("LoadScenarioVersion: %v", err)
+	}
+	if ok {
+		t.Fatal("got ok=true, want false: no scenario.yaml was written")
+	}
+	if v.MinGoVersion != "" || v.MaxGoVersion != "" || len(v.Tags) != 0 {
+		t.Errorf("got %+v, want zero value", v)
+	}
+}
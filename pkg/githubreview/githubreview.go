@@ -0,0 +1,237 @@
+// Package githubreview posts the patcher's suggested fixes as a GitHub
+// pull request review, for a CI workflow that runs repo-patcher in
+// -dry-run mode and wants a human to see the diffs inline on the PR
+// instead of (or in addition to) reading the job log.
+package githubreview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// DefaultBaseURL is the GitHub REST API's base URL.
+const DefaultBaseURL = "https://api.github.com"
+
+// Options configures a GitHubReviewer.
+type Options struct {
+	// Token authenticates as a GitHub App installation or PAT with
+	// pull-request write access - normally $GITHUB_TOKEN in a GitHub
+	// Actions job.
+	Token string
+	// Owner and Repo identify the repository the PR lives in.
+	Owner string
+	Repo  string
+	// PullNumber is the pull request to review.
+	PullNumber int
+	// BaseURL overrides DefaultBaseURL; tests point it at an
+	// httptest.Server.
+	BaseURL string
+	// HTTPClient overrides http.DefaultClient.
+	HTTPClient *http.Client
+	// MaxRetries caps how many times a rate-limited request is retried
+	// before SubmitReview gives up. Zero means DefaultMaxRetries.
+	MaxRetries int
+}
+
+// DefaultMaxRetries is how many times a rate-limited request is retried
+// before Options.MaxRetries's zero value falls back to it.
+const DefaultMaxRetries = 5
+
+func (o Options) withDefaults() Options {
+	if o.BaseURL == "" {
+		o.BaseURL = DefaultBaseURL
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultMaxRetries
+	}
+	return o
+}
+
+// DetectOptions builds Options from the environment variables a GitHub
+// Actions job running on a pull_request event sets: GITHUB_TOKEN,
+// GITHUB_REPOSITORY ("owner/repo"), and GITHUB_PR_NUMBER. It reports false
+// if any of the three is unset or malformed, so a caller can fall back to
+// writing files directly when it's not running inside such a workflow.
+func DetectOptions() (Options, bool) {
+	token := os.Getenv("GITHUB_TOKEN")
+	repoSlug := os.Getenv("GITHUB_REPOSITORY")
+	prNumber := os.Getenv("GITHUB_PR_NUMBER")
+	if token == "" || repoSlug == "" || prNumber == "" {
+		return Options{}, false
+	}
+
+	owner, repo, ok := strings.Cut(repoSlug, "/")
+	if !ok || owner == "" || repo == "" {
+		return Options{}, false
+	}
+
+	n, err := strconv.Atoi(prNumber)
+	if err != nil || n <= 0 {
+		return Options{}, false
+	}
+
+	return Options{Token: token, Owner: owner, Repo: repo, PullNumber: n}, true
+}
+
+// GitHubReviewer posts a batch of patch.Results as a single GitHub pull
+// request review.
+type GitHubReviewer struct {
+	opts Options
+}
+
+// NewGitHubReviewer returns a GitHubReviewer configured by opts.
+func NewGitHubReviewer(opts Options) *GitHubReviewer {
+	return &GitHubReviewer{opts: opts.withDefaults()}
+}
+
+// reviewComment is one entry in a "create a review" request's comments
+// array (POST /repos/{owner}/{repo}/pulls/{pull_number}/reviews).
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Body string `json:"body"`
+}
+
+// createReviewRequest is POST .../pulls/{pull_number}/reviews's body.
+type createReviewRequest struct {
+	Body     string          `json:"body"`
+	Event    string          `json:"event"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// commentBody renders result as a review comment body: a one-line summary
+// of the fix kind, followed by a fenced diff block so the suggestion
+// renders the same way GitHub renders a suggested-change diff.
+func commentBody(result patch.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "repo-patcher suggests a %s fix:\n\n", result.Kind)
+	b.WriteString("```diff\n")
+	b.WriteString(strings.TrimRight(result.DiffOutput, "\n"))
+	b.WriteString("\n```\n")
+	return b.String()
+}
+
+// SubmitReview posts every result with a non-empty DiffOutput (only
+// populated by a -dry-run pass - see patch.Result) as one batched GitHub
+// pull request review, rather than one API call per comment. It reports
+// no error and does nothing if results has no such entry.
+func (r *GitHubReviewer) SubmitReview(ctx context.Context, results []patch.Result) error {
+	var comments []reviewComment
+	for _, result := range results {
+		if result.DiffOutput == "" {
+			continue
+		}
+		comments = append(comments, reviewComment{
+			Path: result.File,
+			Line: result.AfterEnd,
+			Body: commentBody(result),
+		})
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	body := createReviewRequest{
+		Body:     fmt.Sprintf("repo-patcher found %d suggested fix(es).", len(comments)),
+		Event:    "COMMENT",
+		Comments: comments,
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("githubreview: marshal review: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/reviews", r.opts.BaseURL, r.opts.Owner, r.opts.Repo, r.opts.PullNumber)
+	return r.postWithBackoff(ctx, url, payload)
+}
+
+// postWithBackoff POSTs payload to url, retrying on a rate-limit response
+// (403 or 429) with exponential backoff plus jitter, honoring a
+// Retry-After header when the API sends one. It gives up after
+// r.opts.MaxRetries attempts.
+func (r *GitHubReviewer) postWithBackoff(ctx context.Context, url string, payload []byte) error {
+	var lastErr error
+	var lastRetryAfter time.Duration
+	for attempt := 0; attempt <= r.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := backoffDelay(attempt, lastRetryAfter)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("githubreview: build request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+r.opts.Token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := r.opts.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("githubreview: %w", err)
+			continue
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+			return nil
+		}
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+			lastRetryAfter = retryAfterSeconds(resp.Header)
+			lastErr = fmt.Errorf("githubreview: rate limited (status %d): %s", resp.StatusCode, respBody)
+			continue
+		}
+		return fmt.Errorf("githubreview: unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+	return fmt.Errorf("githubreview: giving up after %d retries: %w", r.opts.MaxRetries, lastErr)
+}
+
+// retryAfterSeconds parses a Retry-After response header (seconds, the
+// form GitHub's rate limiter sends) into a Duration, or 0 if absent or
+// malformed.
+func retryAfterSeconds(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// backoffDelay returns how long to wait before retry attempt n (1-based):
+// retryAfter if the server gave one, otherwise 2^(n-1) seconds capped at
+// 30s, plus up to 250ms of jitter so concurrent retries from multiple
+// workflow runs don't all land on the API in lockstep.
+func backoffDelay(n int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	base := time.Duration(1<<uint(n-1)) * time.Second
+	const cap = 30 * time.Second
+	if base > cap {
+		base = cap
+	}
+	return base + time.Duration(rand.Intn(250))*time.Millisecond
+}
@@ -0,0 +1,223 @@
+package githubreview_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/githubreview"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestSubmitReviewBatchesCommentsIntoOneReview(t *testing.T) {
+	var gotPath string
+	var gotBody struct {
+		Body     string `json:"body"`
+		Event    string `json:"event"`
+		Comments []struct {
+			Path string `json:"path"`
+			Line int    `json:"line"`
+			Body string `json:"body"`
+		} `json:"comments"`
+	}
+	var requestCount int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer test-token")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	reviewer := githubreview.NewGitHubReviewer(githubreview.Options{
+		Token:      "test-token",
+		Owner:      "FernandoTN",
+		Repo:       "repo-patcher",
+		PullNumber: 42,
+		BaseURL:    server.URL,
+	})
+
+	results := []patch.Result{
+		{File: "a.go", Kind: patch.KindAddImport, AfterEnd: 3, DiffOutput: "-old\n+new\n"},
+		{File: "b.go", Kind: patch.KindRemoveImport, AfterEnd: 7, DiffOutput: "-old2\n+new2\n"},
+		{File: "c.go", Kind: patch.KindReplaceCall}, // no DiffOutput: not a dry run, must be skipped
+	}
+
+	if err := reviewer.SubmitReview(context.Background(), results); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+
+	if requestCount != 1 {
+		t.Fatalf("requestCount = %d, want exactly one batched request", requestCount)
+	}
+	if gotPath != "/repos/FernandoTN/repo-patcher/pulls/42/reviews" {
+		t.Errorf("request path = %q", gotPath)
+	}
+	if gotBody.Event != "COMMENT" {
+		t.Errorf("event = %q, want COMMENT", gotBody.Event)
+	}
+	if len(gotBody.Comments) != 2 {
+		t.Fatalf("got %d comments, want 2 (one per dry-run result)", len(gotBody.Comments))
+	}
+	for _, c := range gotBody.Comments {
+		if !strings.Contains(c.Body, "```diff\n") {
+			t.Errorf("comment body = %q, want a fenced diff block", c.Body)
+		}
+	}
+}
+
+func TestSubmitReviewRetriesOnRateLimit(t *testing.T) {
+	var requestCount int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	reviewer := githubreview.NewGitHubReviewer(githubreview.Options{
+		Token:      "test-token",
+		Owner:      "o",
+		Repo:       "r",
+		PullNumber: 1,
+		BaseURL:    server.URL,
+	})
+
+	results := []patch.Result{{File: "a.go", DiffOutput: "-x\n+y\n"}}
+	if err := reviewer.SubmitReview(context.Background(), results); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if requestCount != 3 {
+		t.Fatalf("requestCount = %d, want 3 (two rate-limited retries then success)", requestCount)
+	}
+}
+
+// failFirstTransport fails the first request it sees with a connection
+// error, then delegates every later request to http.DefaultTransport. A
+// transport-level failure (unlike a rate-limited response) never touches
+// backoffDelay's retryAfter hint, which is exactly the gap a leaked
+// Retry-After from an earlier, unrelated SubmitReview call would fall
+// through.
+type failFirstTransport struct {
+	failed bool
+}
+
+func (tr *failFirstTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !tr.failed {
+		tr.failed = true
+		return nil, fmt.Errorf("simulated connection error")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSubmitReviewDoesNotLeakRetryAfterAcrossCalls(t *testing.T) {
+	var firstCount int
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		firstCount++
+		if firstCount == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer first.Close()
+
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer second.Close()
+
+	reviewer := githubreview.NewGitHubReviewer(githubreview.Options{
+		Token: "test-token", Owner: "o", Repo: "r", PullNumber: 1, BaseURL: first.URL,
+	})
+	results := []patch.Result{{File: "a.go", DiffOutput: "-x\n+y\n"}}
+
+	if err := reviewer.SubmitReview(context.Background(), results); err != nil {
+		t.Fatalf("first SubmitReview: %v", err)
+	}
+
+	reviewer = githubreview.NewGitHubReviewer(githubreview.Options{
+		Token:      "test-token",
+		Owner:      "o",
+		Repo:       "r",
+		PullNumber: 2,
+		BaseURL:    second.URL,
+		HTTPClient: &http.Client{Transport: &failFirstTransport{}},
+	})
+	start := time.Now()
+	if err := reviewer.SubmitReview(context.Background(), results); err != nil {
+		t.Fatalf("second SubmitReview: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// The second reviewer's first attempt fails with a plain connection
+	// error, not a rate-limited response, so it never sets its own
+	// retryAfter hint. Its one retry should fall back to the default
+	// ~1s exponential backoff rather than reusing the 3s Retry-After
+	// the first, unrelated SubmitReview call saw. A leaked value would
+	// make this take at least 3s; a comfortably smaller bound catches
+	// that without being sensitive to ordinary scheduling jitter.
+	if elapsed >= 2*time.Second {
+		t.Errorf("second SubmitReview took %v, want well under 2s: a stale Retry-After leaked from the first, unrelated call", elapsed)
+	}
+}
+
+func TestSubmitReviewNoDryRunResultsIsNoop(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	reviewer := githubreview.NewGitHubReviewer(githubreview.Options{
+		Token: "t", Owner: "o", Repo: "r", PullNumber: 1, BaseURL: server.URL,
+	})
+
+	if err := reviewer.SubmitReview(context.Background(), []patch.Result{{File: "a.go"}}); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if called {
+		t.Error("SubmitReview made an API call with no dry-run results to submit")
+	}
+}
+
+func TestDetectOptions(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "tok")
+	t.Setenv("GITHUB_REPOSITORY", "FernandoTN/repo-patcher")
+	t.Setenv("GITHUB_PR_NUMBER", "7")
+
+	opts, ok := githubreview.DetectOptions()
+	if !ok {
+		t.Fatal("DetectOptions reported false with all three env vars set")
+	}
+	if opts.Owner != "FernandoTN" || opts.Repo != "repo-patcher" || opts.PullNumber != 7 {
+		t.Errorf("got %+v", opts)
+	}
+}
+
+func TestDetectOptionsMissingEnvVar(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_REPOSITORY", "FernandoTN/repo-patcher")
+	t.Setenv("GITHUB_PR_NUMBER", "7")
+
+	if _, ok := githubreview.DetectOptions(); ok {
+		t.Error("DetectOptions reported true with GITHUB_TOKEN unset")
+	}
+}
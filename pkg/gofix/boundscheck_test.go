@@ -0,0 +1,122 @@
+package gofix
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestBoundsCheckFixerGuardsBothOutOfRangeAccesses(t *testing.T) {
+	const src = `package p
+
+func process() (int, error) {
+	s := []int{1, 2, 3}
+	x := s[5]
+	y := s[7]
+	if len(s) > 2 {
+		z := s[2]
+		_ = z
+	}
+	return x + y, nil
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (BoundsCheckFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"if len(s) <= 5 {",
+		"if len(s) <= 7 {",
+		"return 0, ErrOutOfBounds",
+		`var ErrOutOfBounds = errors.New("gofix: index out of bounds")`,
+		`"errors"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got:\n%s\nwant %q", out, want)
+		}
+	}
+	if strings.Contains(out, "if len(s) <= 2 {") {
+		t.Errorf("got:\n%s\nwant the already-guarded s[2] access left untouched", out)
+	}
+}
+
+func TestBoundsCheckFixerIgnoresInBoundsAccess(t *testing.T) {
+	const src = `package p
+
+func first() int {
+	s := []int{1, 2, 3}
+	return s[0]
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (BoundsCheckFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: s[0] is in range for a 3-element literal")
+	}
+}
+
+func TestBoundsCheckFixerDoesNotRedeclareExistingSentinel(t *testing.T) {
+	const src = `package p
+
+import "errors"
+
+var ErrOutOfBounds = errors.New("already here")
+
+func get() (int, error) {
+	s := []int{1, 2, 3}
+	return s[9], nil
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (BoundsCheckFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	if strings.Count(out, "ErrOutOfBounds = errors.New") != 1 {
+		t.Errorf("got:\n%s\nwant exactly one ErrOutOfBounds declaration", out)
+	}
+	if !strings.Contains(out, `"already here"`) {
+		t.Errorf("got:\n%s\nwant the file's existing ErrOutOfBounds left alone", out)
+	}
+}
@@ -0,0 +1,112 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/build/constraint"
+)
+
+// BuildConstraintFixer synthesizes a missing "//go:build" or "// +build"
+// comment for a file that has only one of the two forms, so old and new
+// toolchains agree on the file's build constraint instead of one silently
+// ignoring it. Like InitOrderFixer, there's no compiler diagnostic for
+// this - a file with only one form builds and vets just fine - so it isn't
+// wired through the Fixer/Registry diagnostic pipeline; a caller runs it
+// directly over a file's source.
+type BuildConstraintFixer struct{}
+
+// Fix delegates to FixBuildConstraints.
+func (BuildConstraintFixer) Fix(src []byte) ([]byte, bool, error) {
+	return FixBuildConstraints(src)
+}
+
+// FixBuildConstraints scans src's leading comments (the lines before the
+// package clause) for "//go:build" and "// +build" constraint comments. If
+// it finds exactly one of the two forms, it synthesizes the other from it
+// and returns the rewritten source. If it finds both, neither, or a form
+// it can't parse, it returns src unchanged.
+func FixBuildConstraints(src []byte) ([]byte, bool, error) {
+	lines := bytes.Split(src, []byte("\n"))
+
+	packageIdx := -1
+	for i, line := range lines {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte("package ")) || bytes.Equal(bytes.TrimSpace(line), []byte("package")) {
+			packageIdx = i
+			break
+		}
+	}
+	if packageIdx < 0 {
+		return src, false, nil
+	}
+
+	var goBuildIdx = -1
+	var plusBuildIdxs []int
+	for i := 0; i < packageIdx; i++ {
+		text := string(bytes.TrimRight(lines[i], "\r"))
+		switch {
+		case constraint.IsGoBuild(text):
+			goBuildIdx = i
+		case constraint.IsPlusBuild(text):
+			plusBuildIdxs = append(plusBuildIdxs, i)
+		}
+	}
+
+	hasGoBuild := goBuildIdx >= 0
+	hasPlusBuild := len(plusBuildIdxs) > 0
+	if hasGoBuild == hasPlusBuild {
+		// Both present (already in sync, or at least not this fixer's
+		// business to reconcile them) or neither present.
+		return src, false, nil
+	}
+
+	var inserted [][]byte
+	var insertAt int
+	if hasPlusBuild {
+		expr, err := andPlusBuildLines(lines, plusBuildIdxs)
+		if err != nil {
+			return src, false, nil
+		}
+		insertAt = plusBuildIdxs[0]
+		inserted = [][]byte{[]byte(fmt.Sprintf("//go:build %s", expr.String()))}
+	} else {
+		text := string(bytes.TrimRight(lines[goBuildIdx], "\r"))
+		expr, err := constraint.Parse(text)
+		if err != nil {
+			return src, false, nil
+		}
+		plusLines, err := constraint.PlusBuildLines(expr)
+		if err != nil {
+			return src, false, nil
+		}
+		insertAt = goBuildIdx + 1
+		for _, l := range plusLines {
+			inserted = append(inserted, []byte(l))
+		}
+	}
+
+	out := make([][]byte, 0, len(lines)+len(inserted))
+	out = append(out, lines[:insertAt]...)
+	out = append(out, inserted...)
+	out = append(out, lines[insertAt:]...)
+	return bytes.Join(out, []byte("\n")), true, nil
+}
+
+// andPlusBuildLines parses every "// +build" line at idxs in lines and
+// combines them with AND, mirroring how multiple "// +build" lines are
+// already implicitly combined by the build system.
+func andPlusBuildLines(lines [][]byte, idxs []int) (constraint.Expr, error) {
+	var x constraint.Expr
+	for _, i := range idxs {
+		text := string(bytes.TrimRight(lines[i], "\r"))
+		y, err := constraint.Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		if x == nil {
+			x = y
+		} else {
+			x = &constraint.AndExpr{X: x, Y: y}
+		}
+	}
+	return x, nil
+}
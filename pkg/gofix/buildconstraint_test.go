@@ -0,0 +1,125 @@
+package gofix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFixBuildConstraintsAddsPlusBuildFromGoBuild(t *testing.T) {
+	const src = `//go:build linux
+
+package p
+`
+	const want = `//go:build linux
+// +build linux
+
+package p
+`
+	out, changed, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestFixBuildConstraintsAddsGoBuildFromPlusBuild(t *testing.T) {
+	const src = `// +build linux
+
+package p
+`
+	const want = `//go:build linux
+// +build linux
+
+package p
+`
+	out, changed, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestFixBuildConstraintsHandlesNegation(t *testing.T) {
+	const src = `//go:build !linux
+
+package p
+`
+	out, _, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if !strings.Contains(string(out), "// +build !linux") {
+		t.Errorf("got:\n%s\nwant a \"// +build !linux\" line", out)
+	}
+}
+
+func TestFixBuildConstraintsHandlesAnd(t *testing.T) {
+	const src = `//go:build linux && amd64
+
+package p
+`
+	out, _, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if !strings.Contains(string(out), "// +build linux,amd64") {
+		t.Errorf("got:\n%s\nwant a \"// +build linux,amd64\" line", out)
+	}
+}
+
+func TestFixBuildConstraintsHandlesOr(t *testing.T) {
+	const src = `//go:build linux || darwin
+
+package p
+`
+	out, _, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if !strings.Contains(string(out), "// +build linux darwin") {
+		t.Errorf("got:\n%s\nwant a \"// +build linux darwin\" line", out)
+	}
+}
+
+func TestFixBuildConstraintsLeavesBothFormsAlone(t *testing.T) {
+	const src = `//go:build linux
+// +build linux
+
+package p
+`
+	out, changed, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if changed {
+		t.Error("reported a change for a file that already has both forms")
+	}
+	if string(out) != src {
+		t.Errorf("got:\n%s\nwant unchanged:\n%s", out, src)
+	}
+}
+
+func TestFixBuildConstraintsLeavesNeitherFormAlone(t *testing.T) {
+	const src = `package p
+`
+	out, changed, err := FixBuildConstraints([]byte(src))
+	if err != nil {
+		t.Fatalf("FixBuildConstraints: %v", err)
+	}
+	if changed {
+		t.Error("reported a change for a file with no build constraint at all")
+	}
+	if string(out) != src {
+		t.Errorf("got:\n%s\nwant unchanged:\n%s", out, src)
+	}
+}
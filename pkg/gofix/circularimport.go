@@ -0,0 +1,729 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// CrossingIdentifier is one exported identifier a cycle's CircularImportDetector
+// found referenced across a cycle edge: Package is the import path that
+// currently declares it, Name its identifier.
+type CrossingIdentifier struct {
+	Package string
+	Name    string
+}
+
+// CycleSuggestion is one import cycle CircularImportDetector found: the
+// packages involved, the identifiers that would need to move to break it,
+// and a name for the package they could move into. It is always reported,
+// whether or not CircularImportFixer can act on it - see CycleSuggestion's
+// use in CircularImportFixer.Extract.
+type CycleSuggestion struct {
+	// Cycle lists the distinct import paths making up the cycle, in
+	// dependency order (Cycle[i] imports Cycle[i+1], wrapping back to
+	// Cycle[0]). It has no repeated trailing element.
+	Cycle []string
+	// CrossingIdentifiers is the minimal set of exported identifiers that
+	// cross the cycle's boundaries - what a split would need to relocate.
+	CrossingIdentifiers []CrossingIdentifier
+	// NewPackageName is a suggested name for the package
+	// CrossingIdentifiers should move into, derived from the common
+	// prefix of the cycled packages' own names ("shared" if they have
+	// none).
+	NewPackageName string
+}
+
+// CircularImportDetector builds the import graph of a module and reports
+// every cycle in it. Unlike most analyses in this package, it can't use
+// go/packages (see WorkspaceLoader, ModuleResolver) or go/types to resolve
+// anything: the go command itself refuses to even list a package whose
+// import graph contains a genuine cycle ("import cycle not allowed"),
+// which is exactly the input this detector exists to diagnose. Instead it
+// parses every file under a module directly and follows plain import
+// path strings, the same syntactic approach PackageSizeSuggester uses for
+// its own advisory-only analysis.
+type CircularImportDetector struct{}
+
+// Detect walks moduleDir (which must contain a go.mod) and returns one
+// CycleSuggestion per import cycle found among its own packages, in a
+// deterministic order.
+func (CircularImportDetector) Detect(moduleDir string) ([]CycleSuggestion, error) {
+	fset := token.NewFileSet()
+	_, filesByPkg, _, err := discoverModulePackages(fset, moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cycles := findCycles(importGraph(filesByPkg))
+	sort.Slice(cycles, func(i, j int) bool { return strings.Join(cycles[i], ",") < strings.Join(cycles[j], ",") })
+
+	var suggestions []CycleSuggestion
+	for _, cycle := range cycles {
+		suggestions = append(suggestions, CycleSuggestion{
+			Cycle:               cycle,
+			CrossingIdentifiers: crossingIdentifiers(cycle, filesByPkg),
+			NewPackageName:      extractedPackageName(cycle),
+		})
+	}
+	return suggestions, nil
+}
+
+// CircularImportFixer extracts a CycleSuggestion's crossing identifiers
+// into a new package both cycled packages can depend on instead of each
+// other. It only ever does this for a two-package cycle: a cycle spanning
+// three or more packages has no single "this side" and "that side" to
+// extract from, and picking which edge of the ring to cut is a judgment
+// call CircularImportDetector leaves as a suggestion rather than an
+// automatic rewrite.
+type CircularImportFixer struct{}
+
+// Extract performs the extraction s describes, writing the new package
+// and rewriting both cycled packages' files on disk, and reports the
+// patch.Result for each file it touched. It is a no-op (nil, nil) for any
+// suggestion that isn't a two-package cycle, or that has no crossing
+// identifiers to move.
+//
+// Extract only rewrites a crossing identifier's references through the
+// other package's import qualifier - a moved identifier also called
+// unqualified from within the package that used to declare it is left
+// as-is, since that call site looks like any other local call and isn't
+// particular to the cycle being broken.
+func (CircularImportFixer) Extract(moduleDir string, s CycleSuggestion) ([]patch.Result, error) {
+	if len(s.Cycle) != 2 || len(s.CrossingIdentifiers) == 0 {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	modulePath, filesByPkg, fileOf, err := discoverModulePackages(fset, moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	newSource, newFilePath, touched, err := extractCycle(fset, modulePath, moduleDir, filesByPkg, s)
+	if err != nil || newSource == nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(touched)+1)
+	for file := range touched {
+		paths = append(paths, fileOf[file])
+	}
+	paths = append(paths, newFilePath)
+
+	txn, err := patch.Begin(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []patch.Result
+	for file := range touched {
+		p := fileOf[file]
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, file); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+		before := txn.Read(p)
+		if bytes.Equal(patched, before) {
+			continue
+		}
+		bs, be, as, ae := patch.DiffLines(before, patched)
+		txn.Write(p, patched)
+		results = append(results, patch.Result{
+			File: p, Kind: patch.KindPackageExtraction, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newFilePath), 0o755); err != nil {
+		_ = txn.Rollback()
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	txn.Write(newFilePath, newSource)
+	results = append(results, patch.Result{
+		File: newFilePath, Kind: patch.KindPackageExtraction, Changed: true,
+		AfterStart: 1, AfterEnd: bytes.Count(newSource, []byte("\n")) + 1,
+	})
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
+
+// extractCycle mutates filesByPkg's two packages in place - cutting each
+// moved declaration out of its original file and rewriting references to
+// it - and returns the new package's source, the path it should be
+// written to, and the set of original files it changed. It returns nil
+// source if there was nothing to move.
+//
+// The new package's source is assembled as text (format.Node per moved
+// declaration, then format.Source over the whole file) rather than by
+// building one *ast.File out of decls cut from several original files:
+// those decls' token.Pos values stay valid against fset for printing
+// individually, but the printer also uses position order to place
+// comments when handed a whole *ast.File, and decls pulled from two
+// different source files have no consistent relative order - so printing
+// them together as one file scrambles which doc comment lands on which
+// declaration.
+func extractCycle(fset *token.FileSet, modulePath, moduleDir string, filesByPkg map[string][]*ast.File, s CycleSuggestion) (newSource []byte, newFilePath string, touched map[*ast.File]bool, err error) {
+	touched = map[*ast.File]bool{}
+	movedByPkg := map[string]map[string]bool{}
+	for _, ci := range s.CrossingIdentifiers {
+		if movedByPkg[ci.Package] == nil {
+			movedByPkg[ci.Package] = map[string]bool{}
+		}
+		movedByPkg[ci.Package][ci.Name] = true
+	}
+
+	pkgPaths := make([]string, 0, len(movedByPkg))
+	for pkgPath := range movedByPkg {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	var declTexts []string
+	var importPaths []neededImport
+	seenImport := map[string]bool{}
+	for _, pkgPath := range pkgPaths {
+		names := movedByPkg[pkgPath]
+		for _, file := range filesByPkg[pkgPath] {
+			kept := make([]ast.Decl, 0, len(file.Decls))
+			var movedDocs []*ast.CommentGroup
+			for _, decl := range file.Decls {
+				if !allNamesMoved(decl, names) {
+					kept = append(kept, decl)
+					continue
+				}
+				var buf bytes.Buffer
+				if err := format.Node(&buf, fset, decl); err != nil {
+					return nil, "", nil, fmt.Errorf("gofix: %w", err)
+				}
+				declTexts = append(declTexts, buf.String())
+				for _, imp := range neededImportPaths(decl, file) {
+					if !seenImport[imp.path] {
+						seenImport[imp.path] = true
+						importPaths = append(importPaths, imp)
+					}
+				}
+				if doc := declDoc(decl); doc != nil {
+					movedDocs = append(movedDocs, doc)
+				}
+				touched[file] = true
+			}
+			file.Decls = kept
+			if len(movedDocs) > 0 {
+				file.Comments = removeCommentGroups(file.Comments, movedDocs)
+			}
+		}
+	}
+	if len(declTexts) == 0 {
+		return nil, "", nil, nil
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", s.NewPackageName)
+	if len(importPaths) > 0 {
+		src.WriteString("import (\n")
+		for _, imp := range importPaths {
+			if imp.alias != "" {
+				fmt.Fprintf(&src, "\t%s %q\n", imp.alias, imp.path)
+			} else {
+				fmt.Fprintf(&src, "\t%q\n", imp.path)
+			}
+		}
+		src.WriteString(")\n\n")
+	}
+	src.WriteString(strings.Join(declTexts, "\n\n"))
+	src.WriteString("\n")
+
+	newSource, err = format.Source(src.Bytes())
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("gofix: format extracted package %s: %w", s.NewPackageName, err)
+	}
+
+	newPkgPath := modulePath + "/" + s.NewPackageName
+	for _, pkgPath := range s.Cycle {
+		other := otherPackage(s.Cycle, pkgPath)
+		movedFromOther := movedByPkg[other]
+		if len(movedFromOther) == 0 {
+			continue
+		}
+		for _, file := range filesByPkg[pkgPath] {
+			alias := importAlias(file, other)
+			if alias == "" {
+				continue
+			}
+			changed := false
+			ast.Inspect(file, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				x, ok := sel.X.(*ast.Ident)
+				if !ok || x.Name != alias || !movedFromOther[sel.Sel.Name] {
+					return true
+				}
+				x.Name = s.NewPackageName
+				changed = true
+				return true
+			})
+			if !changed {
+				continue
+			}
+			astutil.AddImport(fset, file, newPkgPath)
+			if !fileReferencesAlias(file, alias) {
+				// astutil.DeleteImport only removes an unaliased import
+				// spec (it's DeleteNamedImport(fset, f, "", path) under
+				// the hood); a crossing import as common as
+				// `b "cycletest/billing"` would otherwise survive the
+				// extraction untouched, leaving the cycle it was meant to
+				// break still in place.
+				_ = deleteImportByAlias(fset, file, other)
+			}
+			touched[file] = true
+		}
+	}
+
+	return newSource, filepath.Join(moduleDir, s.NewPackageName, s.NewPackageName+".go"), touched, nil
+}
+
+// otherPackage returns the member of a two-package cycle that isn't pkgPath.
+func otherPackage(cycle []string, pkgPath string) string {
+	for _, c := range cycle {
+		if c != pkgPath {
+			return c
+		}
+	}
+	return ""
+}
+
+// allNamesMoved reports whether every name decl declares is in names - a
+// grouped `var (...)`/`const (...)` block is moved as a whole or not at
+// all, since splitting one apart isn't something Extract attempts.
+func allNamesMoved(decl ast.Decl, names map[string]bool) bool {
+	declared := declNames(decl)
+	if len(declared) == 0 {
+		return false
+	}
+	for _, n := range declared {
+		if !names[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// declNames returns every top-level name decl introduces: a non-method
+// FuncDecl's own name, or every TypeSpec/ValueSpec name in a GenDecl.
+func declNames(decl ast.Decl) []string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil {
+			return nil
+		}
+		return []string{d.Name.Name}
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range d.Specs {
+			switch sp := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, sp.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range sp.Names {
+					names = append(names, n.Name)
+				}
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// declDoc returns decl's doc comment group, if any, so extractCycle can
+// move it along with decl instead of leaving it to float, orphaned, above
+// whatever decl happens to follow it in the original file.
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return d.Doc
+	case *ast.GenDecl:
+		return d.Doc
+	}
+	return nil
+}
+
+// removeCommentGroups returns comments with every group in docs removed, by
+// identity.
+func removeCommentGroups(comments []*ast.CommentGroup, docs []*ast.CommentGroup) []*ast.CommentGroup {
+	remove := make(map[*ast.CommentGroup]bool, len(docs))
+	for _, d := range docs {
+		remove[d] = true
+	}
+	kept := make([]*ast.CommentGroup, 0, len(comments))
+	for _, c := range comments {
+		if !remove[c] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// neededImport is one import a moved decl's body refers to through a
+// selector: its path, and the explicit alias (if any) the file that used
+// to hold decl gave it. A moved decl's own selector expressions keep
+// printing whatever *ast.Ident name they always had - format.Node just
+// reproduces the original source - so the new package's import block has
+// to declare that same alias, not just the bare path, or the moved code
+// won't resolve it.
+type neededImport struct {
+	path  string
+	alias string // "" if the original file imported path unaliased
+}
+
+// neededImportPaths returns the imports, from file's own imports, that
+// decl's body refers to through a selector - the imports that must travel
+// with decl, alias and all, when it moves to a new file.
+func neededImportPaths(decl ast.Decl, file *ast.File) []neededImport {
+	used := map[string]bool{}
+	ast.Inspect(decl, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			used[x.Name] = true
+		}
+		return true
+	})
+
+	var needed []neededImport
+	for _, imp := range file.Imports {
+		if !used[importSpecAlias(imp)] {
+			continue
+		}
+		alias := ""
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		needed = append(needed, neededImport{path: strings.Trim(imp.Path.Value, `"`), alias: alias})
+	}
+	return needed
+}
+
+// deleteImportByAlias removes file's import of path, the same way it was
+// declared: as a named import if the file gave it an explicit alias,
+// or via astutil.DeleteImport (which only matches an unaliased spec)
+// otherwise. It reports whether an import was actually found and removed.
+func deleteImportByAlias(fset *token.FileSet, file *ast.File, path string) bool {
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != path {
+			continue
+		}
+		if imp.Name != nil {
+			return astutil.DeleteNamedImport(fset, file, imp.Name.Name, path)
+		}
+		break
+	}
+	return astutil.DeleteImport(fset, file, path)
+}
+
+// importSpecAlias returns the local identifier an import is known by in
+// its file: its explicit alias, or its path's last segment.
+func importSpecAlias(imp *ast.ImportSpec) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+	path := strings.Trim(imp.Path.Value, `"`)
+	parts := strings.Split(path, "/")
+	return parts[len(parts)-1]
+}
+
+// importAlias returns the local identifier file uses for pkgPath, or ""
+// if file doesn't import it.
+func importAlias(file *ast.File, pkgPath string) string {
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) == pkgPath {
+			return importSpecAlias(imp)
+		}
+	}
+	return ""
+}
+
+// fileReferencesAlias reports whether file still has a selector
+// expression qualified by alias, after Extract has rewritten the
+// references it knows about.
+func fileReferencesAlias(file *ast.File, alias string) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == alias {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// discoverModulePackages parses every non-test .go file under moduleDir
+// with fset and groups the resulting *ast.File values by the import path
+// of the package that declares them, derived from go.mod's module path
+// plus each file's directory rather than from `go list`/go/packages -
+// those shell out to the go command, which refuses to even list a
+// package whose import graph contains a genuine cycle.
+func discoverModulePackages(fset *token.FileSet, moduleDir string) (modulePath string, filesByPkg map[string][]*ast.File, fileOf map[*ast.File]string, err error) {
+	modulePath, err = readModulePath(moduleDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	filesByPkg = map[string][]*ast.File{}
+	fileOf = map[*ast.File]string{}
+	walkErr := filepath.WalkDir(moduleDir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if p != moduleDir && (d.Name() == "vendor" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("gofix: %w", err)
+		}
+		pkgPath, err := importPathFor(moduleDir, modulePath, p)
+		if err != nil {
+			return err
+		}
+		filesByPkg[pkgPath] = append(filesByPkg[pkgPath], f)
+		fileOf[f] = p
+		return nil
+	})
+	if walkErr != nil {
+		return "", nil, nil, walkErr
+	}
+	return modulePath, filesByPkg, fileOf, nil
+}
+
+// readModulePath reads the module path declared by moduleDir's go.mod.
+func readModulePath(moduleDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("gofix: read go.mod: %w", err)
+	}
+	mf, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return "", fmt.Errorf("gofix: parse go.mod: %w", err)
+	}
+	return mf.Module.Mod.Path, nil
+}
+
+// importPathFor derives the import path of the package declared by
+// filePath, moduleDir's module rooted at modulePath.
+func importPathFor(moduleDir, modulePath, filePath string) (string, error) {
+	rel, err := filepath.Rel(moduleDir, filepath.Dir(filePath))
+	if err != nil {
+		return "", fmt.Errorf("gofix: %w", err)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		return modulePath, nil
+	}
+	return modulePath + "/" + rel, nil
+}
+
+// importGraph builds an adjacency list of a module's own packages: an
+// edge from pkgPath to every other in-module package one of its files
+// imports. Imports outside the module are dropped - a cycle can't reach
+// through an already-built, already-acyclic dependency.
+func importGraph(filesByPkg map[string][]*ast.File) map[string][]string {
+	adj := make(map[string][]string, len(filesByPkg))
+	for pkgPath, files := range filesByPkg {
+		seen := map[string]bool{}
+		for _, f := range files {
+			for _, imp := range f.Imports {
+				path := strings.Trim(imp.Path.Value, `"`)
+				if path == pkgPath || seen[path] {
+					continue
+				}
+				if _, ok := filesByPkg[path]; !ok {
+					continue
+				}
+				seen[path] = true
+			}
+		}
+		edges := make([]string, 0, len(seen))
+		for e := range seen {
+			edges = append(edges, e)
+		}
+		sort.Strings(edges)
+		adj[pkgPath] = edges
+	}
+	return adj
+}
+
+// findCycles runs a DFS over adj, reporting every distinct simple cycle
+// it finds via a gray-node back edge, deduped regardless of which node
+// the DFS happened to visit first.
+func findCycles(adj map[string][]string) [][]string {
+	const white, gray, black = 0, 1, 2
+	color := map[string]int{}
+	var stack []string
+	seenKey := map[string]bool{}
+	var cycles [][]string
+
+	names := make([]string, 0, len(adj))
+	for n := range adj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var visit func(n string)
+	visit = func(n string) {
+		color[n] = gray
+		stack = append(stack, n)
+		for _, m := range adj[n] {
+			switch color[m] {
+			case white:
+				visit(m)
+			case gray:
+				idx := 0
+				for i, s := range stack {
+					if s == m {
+						idx = i
+						break
+					}
+				}
+				cycle := rotateCycle(append([]string{}, stack[idx:]...))
+				key := strings.Join(cycle, "->")
+				if !seenKey[key] {
+					seenKey[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[n] = black
+	}
+
+	for _, n := range names {
+		if color[n] == white {
+			visit(n)
+		}
+	}
+	return cycles
+}
+
+// rotateCycle rotates cycle to start at its lexicographically smallest
+// member, so the same cycle found from different DFS entry points
+// canonicalizes to the same slice.
+func rotateCycle(cycle []string) []string {
+	minIdx := 0
+	for i, s := range cycle {
+		if s < cycle[minIdx] {
+			minIdx = i
+		}
+	}
+	return append(append([]string{}, cycle[minIdx:]...), cycle[:minIdx]...)
+}
+
+// crossingIdentifiers returns, for a cycle's consecutive package pairs
+// (cycle[i] importing cycle[i+1]), every exported identifier cycle[i]'s
+// files access through that import.
+func crossingIdentifiers(cycle []string, filesByPkg map[string][]*ast.File) []CrossingIdentifier {
+	seen := map[CrossingIdentifier]bool{}
+	var result []CrossingIdentifier
+	for i, from := range cycle {
+		to := cycle[(i+1)%len(cycle)]
+		for _, file := range filesByPkg[from] {
+			alias := importAlias(file, to)
+			if alias == "" {
+				continue
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				x, ok := sel.X.(*ast.Ident)
+				if !ok || x.Name != alias || !ast.IsExported(sel.Sel.Name) {
+					return true
+				}
+				id := CrossingIdentifier{Package: to, Name: sel.Sel.Name}
+				if !seen[id] {
+					seen[id] = true
+					result = append(result, id)
+				}
+				return true
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// extractedPackageName derives a split target's name from the longest
+// common prefix of the cycled packages' own (last path segment) names,
+// falling back to "shared" when they have none.
+func extractedPackageName(cycle []string) string {
+	bases := make([]string, len(cycle))
+	for i, pkgPath := range cycle {
+		parts := strings.Split(pkgPath, "/")
+		bases[i] = parts[len(parts)-1]
+	}
+
+	prefix := bases[0]
+	for _, b := range bases[1:] {
+		prefix = commonStringPrefix(prefix, b)
+	}
+	prefix = strings.TrimRightFunc(prefix, func(r rune) bool { return !unicode.IsLetter(r) })
+	if prefix == "" {
+		return "shared"
+	}
+	return strings.ToLower(prefix)
+}
+
+func commonStringPrefix(a, b string) string {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
@@ -0,0 +1,250 @@
+package gofix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCycleModule lays out a module in dir with two packages, account and
+// billing, that import each other: account.Summary calls billing.Rate,
+// and billing.Describe calls account.Name - a minimal two-package import
+// cycle. Rate and Name are each referenced only from the other package,
+// never from within their own, which keeps them squarely inside what
+// CircularImportFixer.Extract knows how to rewrite (it doesn't follow an
+// unqualified local call to a moved identifier - see Extract's doc
+// comment).
+func writeCycleModule(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "account"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "billing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "go.mod", "module example.com/cycles\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "account"), "account.go", `package account
+
+import "example.com/cycles/billing"
+
+func Name() string { return "acct" }
+
+func Summary() string { return billing.Rate() }
+`)
+	writeFile(t, filepath.Join(dir, "billing"), "billing.go", `package billing
+
+import "example.com/cycles/account"
+
+func Rate() string { return "10" }
+
+func Describe() string { return account.Name() }
+`)
+}
+
+func TestCircularImportDetectorFindsTwoPackageCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeCycleModule(t, dir)
+
+	suggestions, err := (CircularImportDetector{}).Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("got %d suggestions, want 1: %+v", len(suggestions), suggestions)
+	}
+
+	s := suggestions[0]
+	if len(s.Cycle) != 2 {
+		t.Fatalf("Cycle = %v, want 2 members", s.Cycle)
+	}
+	want := map[CrossingIdentifier]bool{
+		{Package: "example.com/cycles/account", Name: "Name"}: true,
+		{Package: "example.com/cycles/billing", Name: "Rate"}: true,
+	}
+	if len(s.CrossingIdentifiers) != len(want) {
+		t.Fatalf("CrossingIdentifiers = %+v, want %v", s.CrossingIdentifiers, want)
+	}
+	for _, ci := range s.CrossingIdentifiers {
+		if !want[ci] {
+			t.Errorf("unexpected crossing identifier %+v", ci)
+		}
+	}
+	if s.NewPackageName == "" {
+		t.Error("NewPackageName is empty")
+	}
+}
+
+func TestCircularImportDetectorReportsNothingForAcyclicModule(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "util"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "go.mod", "module example.com/acyclic\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "util"), "util.go", "package util\n\nfunc Double(n int) int { return n * 2 }\n")
+	writeFile(t, dir, "main.go", `package main
+
+import "example.com/acyclic/util"
+
+func main() { _ = util.Double(2) }
+`)
+
+	suggestions, err := (CircularImportDetector{}).Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if suggestions != nil {
+		t.Errorf("got %v, want nil: module has no import cycle", suggestions)
+	}
+}
+
+func TestCircularImportFixerExtractsTwoPackageCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeCycleModule(t, dir)
+
+	suggestions, err := (CircularImportDetector{}).Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("got %d suggestions, want 1", len(suggestions))
+	}
+
+	results, err := (CircularImportFixer{}).Extract(dir, suggestions[0])
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3 (account.go, billing.go, new package file): %+v", len(results), results)
+	}
+
+	if _, err := (CircularImportDetector{}).Detect(dir); err != nil {
+		t.Fatalf("Detect after Extract: %v", err)
+	}
+	remaining, err := (CircularImportDetector{}).Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect after Extract: %v", err)
+	}
+	if remaining != nil {
+		t.Errorf("got %v, want nil: Extract should have broken the cycle", remaining)
+	}
+
+	newPkgDir := filepath.Join(dir, suggestions[0].NewPackageName)
+	if _, err := os.Stat(newPkgDir); err != nil {
+		t.Fatalf("new package dir %s: %v", newPkgDir, err)
+	}
+}
+
+// writeAliasedCycleModule lays out the same two-package cycle
+// writeCycleModule does, but with every crossing import given an explicit
+// alias - b for billing, a for account - and with Name depending on an
+// aliased standard-library import (j for encoding/json) of its own, so
+// Extract has to carry that alias into the new package too.
+func writeAliasedCycleModule(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "account"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "billing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "go.mod", "module example.com/aliasedcycles\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "account"), "account.go", `package account
+
+import (
+	b "example.com/aliasedcycles/billing"
+	j "encoding/json"
+)
+
+func Name() string {
+	data, _ := j.Marshal("acct")
+	return string(data)
+}
+
+func Summary() string { return b.Rate() }
+`)
+	writeFile(t, filepath.Join(dir, "billing"), "billing.go", `package billing
+
+import (
+	a "example.com/aliasedcycles/account"
+)
+
+func Rate() string { return "10" }
+
+func Describe() string { return a.Name() }
+`)
+}
+
+func TestCircularImportFixerExtractsTwoPackageCycleWithAliasedImports(t *testing.T) {
+	dir := t.TempDir()
+	writeAliasedCycleModule(t, dir)
+
+	suggestions, err := (CircularImportDetector{}).Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("got %d suggestions, want 1", len(suggestions))
+	}
+
+	results, err := (CircularImportFixer{}).Extract(dir, suggestions[0])
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3 (account.go, billing.go, new package file): %+v", len(results), results)
+	}
+
+	remaining, err := (CircularImportDetector{}).Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect after Extract: %v", err)
+	}
+	if remaining != nil {
+		t.Errorf("got %v, want nil: Extract should have deleted both aliased crossing imports (b and a), not just an unaliased one", remaining)
+	}
+
+	account, err := os.ReadFile(filepath.Join(dir, "account", "account.go"))
+	if err != nil {
+		t.Fatalf("read account.go: %v", err)
+	}
+	if strings.Contains(string(account), `"example.com/aliasedcycles/billing"`) {
+		t.Errorf("account.go still imports billing:\n%s", account)
+	}
+
+	billing, err := os.ReadFile(filepath.Join(dir, "billing", "billing.go"))
+	if err != nil {
+		t.Fatalf("read billing.go: %v", err)
+	}
+	if strings.Contains(string(billing), `"example.com/aliasedcycles/account"`) {
+		t.Errorf("billing.go still imports account:\n%s", billing)
+	}
+
+	newPkgPath := filepath.Join(dir, suggestions[0].NewPackageName, suggestions[0].NewPackageName+".go")
+	newPkg, err := os.ReadFile(newPkgPath)
+	if err != nil {
+		t.Fatalf("read extracted package file: %v", err)
+	}
+	newSrc := string(newPkg)
+	if !strings.Contains(newSrc, `j "encoding/json"`) {
+		t.Errorf("extracted package dropped Name's encoding/json alias:\n%s", newSrc)
+	}
+	if !strings.Contains(newSrc, "j.Marshal") {
+		t.Errorf("extracted package's Name no longer calls through its j alias:\n%s", newSrc)
+	}
+}
+
+func TestCircularImportFixerLeavesLargerCyclesAsSuggestionsOnly(t *testing.T) {
+	dir := t.TempDir()
+	s := CycleSuggestion{
+		Cycle:               []string{"example.com/m/a", "example.com/m/b", "example.com/m/c"},
+		CrossingIdentifiers: []CrossingIdentifier{{Package: "example.com/m/a", Name: "Foo"}},
+		NewPackageName:      "shared",
+	}
+	results, err := (CircularImportFixer{}).Extract(dir, s)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if results != nil {
+		t.Errorf("got %v, want nil: a three-package cycle is suggestion-only", results)
+	}
+}
@@ -0,0 +1,200 @@
+// Package gofix classifies Go compiler and vet diagnostics into structured
+// categories and proposes deterministic fixes for the common, mechanical
+// classes of breakage (missing imports, unused imports/vars, bad casing,
+// and the like) so the agent does not have to spend an LLM call on them.
+//
+// Every AST edit goes through go/format rather than go/printer directly
+// (see astedit.File.Format and the Propose* functions' use of
+// format.Node), so type parameter lists and other Go 1.18+ generics syntax
+// round-trip correctly. Building this package itself requires Go 1.21 or
+// later, matching go.mod.
+package gofix
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Category identifies the class of compiler/vet diagnostic a Fix addresses.
+type Category string
+
+const (
+	CategoryMissingImport       Category = "missing-import"
+	CategoryUnusedImport        Category = "unused-import"
+	CategoryUndeclaredName      Category = "undeclared-name"
+	CategoryTypoInKeyword       Category = "typo-in-keyword"
+	CategoryUnexportedReference Category = "unexported-reference"
+	CategoryUnusedVar           Category = "unused-var"
+	CategoryTypeMismatch        Category = "type-mismatch"
+	CategoryPrintfMismatch      Category = "printf-mismatch"
+	CategoryShadowedVariable    Category = "shadowed-variable"
+	CategoryErrorReturnMismatch Category = "error-return-mismatch"
+	CategoryMissingMethod       Category = "missing-method"
+	CategoryUnknown             Category = "unknown"
+)
+
+// Diagnostic is a single line of `go build`/`go vet` output, parsed into its
+// file position and message.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Col     int
+	Message string
+}
+
+// Fix is a structured, machine-applicable candidate produced by this package
+// before the agent ever considers asking the LLM for help.
+type Fix struct {
+	Category   Category
+	Diagnostic Diagnostic
+	// Package and Symbol are populated for import-related fixes, e.g.
+	// Package="fmt", Symbol="Sprintf".
+	Package string
+	Symbol  string
+	// SourceType and TargetType are populated for CategoryTypeMismatch:
+	// Symbol holds the misassigned variable's name, SourceType its actual
+	// type, TargetType the type the assignment's left-hand side needs.
+	//
+	// TargetType is also populated for CategoryPrintfMismatch, alongside
+	// Verb: there it holds the actual type of the argument passed for
+	// Verb (e.g. "string" for a %d given a string).
+	SourceType string
+	TargetType string
+	// Verb is populated for CategoryPrintfMismatch: the offending format
+	// verb, e.g. "%d".
+	Verb string
+	// CategoryMissingMethod also uses Symbol (the missing method's name),
+	// SourceType (the concrete type, e.g. "*Dog"), and TargetType (the
+	// interface name).
+	// Confidence is 1.0 for exact, auto-applicable matches and lower for
+	// proposals that should be surfaced for review instead of applied.
+	Confidence float64
+}
+
+var diagnosticLineRE = regexp.MustCompile(`^(?P<file>[^:]+):(?P<line>\d+):(?P<col>\d+):\s*(?P<msg>.+)$`)
+
+// ParseDiagnostics parses the combined stdout+stderr of `go build ./...` or
+// `go vet ./...` into individual Diagnostics. Lines that don't match the
+// `file:line:col: message` shape (e.g. "# package/path" headers) are
+// skipped rather than treated as errors. `go vet` prefixes each diagnostic
+// line with "vet: ", which is stripped before matching.
+func ParseDiagnostics(output string) []Diagnostic {
+	var diags []Diagnostic
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "vet: ")
+		m := diagnosticLineRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		d := Diagnostic{File: m[1], Message: m[4]}
+		d.Line = atoiOrZero(m[2])
+		d.Col = atoiOrZero(m[3])
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+var (
+	// undefinedDottedRE matches "undefined: pkg.sym", which the compiler
+	// emits when pkg is already imported but sym isn't a real exported
+	// identifier of it (almost always a casing typo, e.g. strconv.itoa).
+	undefinedDottedRE = regexp.MustCompile(`^undefined: ([\w./]+)\.(\w+)$`)
+	// undefinedBareRE matches "undefined: pkg", which the compiler emits
+	// when pkg itself was never declared - i.e. the import is missing.
+	undefinedBareRE      = regexp.MustCompile(`^undefined: (\w+)$`)
+	unusedImportRE       = regexp.MustCompile(`^"([^"]+)" imported and not used$`)
+	unusedImportQuotedRE = regexp.MustCompile(`^imported and not used: "([^"]+)"$`)
+	declaredNotUsedRE    = regexp.MustCompile(`^(\w+) declared( and| but)? not used$`)
+	unexportedRefRE      = regexp.MustCompile(`^cannot refer to unexported name ([\w./]+)\.(\w+)$`)
+	typoKeywordRE        = regexp.MustCompile(`^(syntax error: )?unexpected (\w+)`)
+	// typeMismatchRE matches the compiler's assignability error, e.g.
+	// `cannot use x (variable of type int) as string value in assignment`.
+	typeMismatchRE = regexp.MustCompile(`^cannot use (\w+) \(variable of type ([^)]+)\) as (\S+) value in assignment$`)
+	// printfMismatchRE matches go vet's printf analyzer wrong-type message,
+	// e.g. `fmt.Printf format %d has arg "oops" of wrong type string`.
+	printfMismatchRE = regexp.MustCompile(`^\S+ format (%\w) has arg .+ of wrong type (\S+)$`)
+	// shadowRE matches go vet's shadow analyzer message, e.g.
+	// `declaration of "err" shadows declaration at describe.go:6:2`.
+	shadowRE = regexp.MustCompile(`^declaration of "(\w+)" shadows declaration at `)
+	// errorReturnMismatchRE matches the compiler's assignment-count error
+	// for `x := f()` where f actually returns two values, e.g.
+	// `assignment mismatch: 1 variable but strconv.Atoi returns 2 values`.
+	errorReturnMismatchRE = regexp.MustCompile(`^assignment mismatch: 1 variable but (\S+) returns 2 values$`)
+	// missingMethodRE matches the compiler's interface-compliance error,
+	// e.g. `cannot use d (variable of type *Dog) as Greeter value in
+	// argument to useGreeter: *Dog does not implement Greeter (missing
+	// method Name)`.
+	missingMethodRE = regexp.MustCompile(`^cannot use .+ as (\w+) value in [^:]+: (\*?\w+) does not implement (\w+) \(missing method (\w+)\)$`)
+)
+
+// Classify inspects a single Diagnostic and, where possible, returns the Fix
+// category it belongs to. The Package/Symbol fields are filled in for
+// import- and reference-related diagnostics; callers in this package turn
+// the result into an actual AST edit.
+func Classify(d Diagnostic) Fix {
+	msg := d.Message
+
+	if m := undefinedBareRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryMissingImport, Diagnostic: d, Package: m[1], Confidence: 1.0}
+	}
+	if m := undefinedDottedRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryUndeclaredName, Diagnostic: d, Package: m[1], Symbol: m[2]}
+	}
+	if m := unusedImportRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryUnusedImport, Diagnostic: d, Package: m[1], Confidence: 1.0}
+	}
+	if m := unusedImportQuotedRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryUnusedImport, Diagnostic: d, Package: m[1], Confidence: 1.0}
+	}
+	if m := declaredNotUsedRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryUnusedVar, Diagnostic: d, Symbol: m[1], Confidence: 1.0}
+	}
+	if m := unexportedRefRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryUnexportedReference, Diagnostic: d, Package: m[1], Symbol: m[2]}
+	}
+	if typoKeywordRE.MatchString(msg) {
+		return Fix{Category: CategoryTypoInKeyword, Diagnostic: d}
+	}
+	if m := typeMismatchRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryTypeMismatch, Diagnostic: d, Symbol: m[1], SourceType: m[2], TargetType: m[3], Confidence: 1.0}
+	}
+	if m := printfMismatchRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryPrintfMismatch, Diagnostic: d, Verb: m[1], TargetType: m[2], Confidence: 1.0}
+	}
+	if m := shadowRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryShadowedVariable, Diagnostic: d, Symbol: m[1]}
+	}
+	if m := errorReturnMismatchRE.FindStringSubmatch(msg); m != nil {
+		return Fix{Category: CategoryErrorReturnMismatch, Diagnostic: d, Symbol: m[1], Confidence: 1.0}
+	}
+	if m := missingMethodRE.FindStringSubmatch(msg); m != nil && m[1] == m[3] {
+		return Fix{Category: CategoryMissingMethod, Diagnostic: d, TargetType: m[1], SourceType: m[2], Symbol: m[4], Confidence: 1.0}
+	}
+	return Fix{Category: CategoryUnknown, Diagnostic: d}
+}
+
+// ClassifyAll is a convenience wrapper that parses raw compiler output and
+// classifies every diagnostic it finds.
+func ClassifyAll(output string) []Fix {
+	diags := ParseDiagnostics(output)
+	fixes := make([]Fix, 0, len(diags))
+	for _, d := range diags {
+		fixes = append(fixes, Classify(d))
+	}
+	return fixes
+}
+
+func atoiOrZero(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
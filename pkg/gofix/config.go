@@ -0,0 +1,166 @@
+package gofix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the schema of a .repopatcher.yaml file.
+type Config struct {
+	// Fixers lists .so plugin paths to load alongside the built-ins (see
+	// LoadPlugins).
+	Fixers []string `yaml:"fixers"`
+	// EnabledFixers, if non-empty, restricts which registered Fixer names
+	// (built-in or plugin) actually run; every name must already be
+	// registered by the time Validate is called. An empty list means
+	// "every registered Fixer", matching today's default behavior.
+	EnabledFixers []string `yaml:"enabled_fixers"`
+	// Exclude holds filepath.Match glob patterns; a file matching any of
+	// them is skipped by the patch passes.
+	Exclude []string `yaml:"exclude"`
+	// ImportResolver selects how missing-import fixes choose a package
+	// path: "stdlib" (the default, only the standard library), "local"
+	// (also search the module's own packages), or "proxy" (also query the
+	// module proxy for third-party packages).
+	ImportResolver string `yaml:"import_resolver"`
+	// DryRun forces dry-run behavior on; it's a floor, not a ceiling - the
+	// CLI's --dry-run flag can turn dry-run on even if this is false, but
+	// a config setting of true can't be overridden back to false from the
+	// command line.
+	DryRun bool `yaml:"dry_run"`
+	// ProvenanceFile, if non-empty, is the suffix RunUnusedPass appends to
+	// a patched file's own path to name its provenance.json sidecar (see
+	// patch.WriteProvenance). Empty disables provenance tracking.
+	ProvenanceFile string `yaml:"provenance_file"`
+	// SSRRules lists structural search-and-replace rules (see SSRRule and
+	// RunSSRPass) to run over every patched path, for API migrations that
+	// aren't driven by any compiler or vet diagnostic.
+	SSRRules []SSRRule `yaml:"ssr_rules"`
+}
+
+// importResolvers are the valid values for Config.ImportResolver.
+var importResolvers = map[string]bool{"": true, "stdlib": true, "local": true, "proxy": true}
+
+// Validate checks that every name in c.EnabledFixers is registered in reg
+// and that ImportResolver, if set, is one of the known values.
+func (c Config) Validate(reg *Registry) error {
+	for _, name := range c.EnabledFixers {
+		if !reg.Has(name) {
+			return fmt.Errorf("gofix: config: enabled_fixers: %q is not a registered fixer", name)
+		}
+	}
+	if !importResolvers[c.ImportResolver] {
+		return fmt.Errorf("gofix: config: import_resolver: %q is not one of stdlib, local, proxy", c.ImportResolver)
+	}
+	return nil
+}
+
+// IsExcluded reports whether path matches one of c.Exclude's glob patterns.
+func (c Config) IsExcluded(path string) bool {
+	for _, pattern := range c.Exclude {
+		if ok, err := filepath.Match(pattern, path); err == nil && ok {
+			return true
+		}
+		if ok, err := filepath.Match(pattern, filepath.Base(path)); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// merge overlays onto base: every non-zero field of overlay replaces
+// base's, except DryRun, which is OR'd so a true set anywhere in the
+// chain can't be cleared by a later, lower-precedence false.
+func merge(base, overlay Config) Config {
+	if overlay.Fixers != nil {
+		base.Fixers = overlay.Fixers
+	}
+	if overlay.EnabledFixers != nil {
+		base.EnabledFixers = overlay.EnabledFixers
+	}
+	if overlay.Exclude != nil {
+		base.Exclude = overlay.Exclude
+	}
+	if overlay.ImportResolver != "" {
+		base.ImportResolver = overlay.ImportResolver
+	}
+	if overlay.ProvenanceFile != "" {
+		base.ProvenanceFile = overlay.ProvenanceFile
+	}
+	if overlay.SSRRules != nil {
+		base.SSRRules = overlay.SSRRules
+	}
+	base.DryRun = base.DryRun || overlay.DryRun
+	return base
+}
+
+// loadConfigFile reads and parses path. A missing file is not an error -
+// the config is entirely optional - and returns the zero Config.
+func loadConfigFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("gofix: read config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("gofix: parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadConfig builds the effective Config for startDir: a user-level
+// ~/.repopatcher.yaml (lowest precedence) overlaid by every
+// .repopatcher.yaml found walking up from startDir to the filesystem root,
+// applied outermost-ancestor first so the directory closest to startDir
+// wins last. Every layer is optional; a tree with no config files at all
+// returns the zero Config.
+func LoadConfig(startDir string) (*Config, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+	return loadConfig(startDir, home)
+}
+
+func loadConfig(startDir, homeDir string) (*Config, error) {
+	cfg := Config{}
+
+	if homeDir != "" {
+		userCfg, err := loadConfigFile(filepath.Join(homeDir, ".repopatcher.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		cfg = merge(cfg, userCfg)
+	}
+
+	abs, err := filepath.Abs(startDir)
+	if err != nil {
+		return nil, fmt.Errorf("gofix: resolve config start dir %s: %w", startDir, err)
+	}
+
+	var ancestors []string
+	for dir := abs; ; {
+		ancestors = append(ancestors, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		dirCfg, err := loadConfigFile(filepath.Join(ancestors[i], ".repopatcher.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		cfg = merge(cfg, dirCfg)
+	}
+
+	return &cfg, nil
+}
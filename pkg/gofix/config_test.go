@@ -0,0 +1,109 @@
+package gofix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// TestLoadConfigMergesAncestorsOverUser builds project/sub as nested
+// directories under a temp root, each with its own .repopatcher.yaml, plus
+// a separate temp "home" directory standing in for ~/.repopatcher.yaml,
+// and checks LoadConfig's walk-up-and-merge picks the most specific value
+// for each field while falling back to ancestors and finally the user
+// config.
+func TestLoadConfigMergesAncestorsOverUser(t *testing.T) {
+	root := t.TempDir()
+	home := t.TempDir()
+	project := filepath.Join(root, "project")
+	sub := filepath.Join(project, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatalf("mkdir sub: %v", err)
+	}
+
+	writeConfig(t, filepath.Join(home, ".repopatcher.yaml"), `
+fixers: ["/home/user/plugin.so"]
+import_resolver: stdlib
+`)
+	writeConfig(t, filepath.Join(project, ".repopatcher.yaml"), `
+import_resolver: local
+exclude: ["*_generated.go"]
+`)
+	writeConfig(t, filepath.Join(sub, ".repopatcher.yaml"), `
+dry_run: true
+`)
+
+	cfg, err := loadConfig(sub, home)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	if len(cfg.Fixers) != 1 || cfg.Fixers[0] != "/home/user/plugin.so" {
+		t.Errorf("Fixers = %v, want inherited from user config", cfg.Fixers)
+	}
+	if cfg.ImportResolver != "local" {
+		t.Errorf("ImportResolver = %q, want %q (project overrides user)", cfg.ImportResolver, "local")
+	}
+	if len(cfg.Exclude) != 1 || cfg.Exclude[0] != "*_generated.go" {
+		t.Errorf("Exclude = %v, want inherited from project config", cfg.Exclude)
+	}
+	if !cfg.DryRun {
+		t.Error("DryRun = false, want true from sub config")
+	}
+}
+
+// TestLoadConfigHandlesMissingFilesGracefully checks a directory tree with
+// no .repopatcher.yaml anywhere, and no readable home directory, returns
+// the zero Config rather than an error.
+func TestLoadConfigHandlesMissingFilesGracefully(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := loadConfig(dir, filepath.Join(dir, "does-not-exist"))
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg == nil || len(cfg.Fixers) != 0 || len(cfg.EnabledFixers) != 0 || cfg.ImportResolver != "" || cfg.DryRun {
+		t.Errorf("loadConfig with no config files = %+v, want zero Config", cfg)
+	}
+}
+
+func TestConfigValidateRejectsUnknownFixer(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("shadowed-variable", ShadowFixer{})
+
+	cfg := Config{EnabledFixers: []string{"does-not-exist"}}
+	if err := cfg.Validate(reg); err == nil {
+		t.Fatal("Validate accepted an unregistered fixer name")
+	}
+
+	cfg = Config{EnabledFixers: []string{"shadowed-variable"}}
+	if err := cfg.Validate(reg); err != nil {
+		t.Errorf("Validate rejected a registered fixer name: %v", err)
+	}
+}
+
+func TestConfigValidateRejectsUnknownImportResolver(t *testing.T) {
+	cfg := Config{ImportResolver: "bogus"}
+	if err := cfg.Validate(NewRegistry()); err == nil {
+		t.Fatal("Validate accepted an unknown import resolver")
+	}
+}
+
+func TestConfigIsExcludedMatchesBaseAndFullPath(t *testing.T) {
+	cfg := Config{Exclude: []string{"*_generated.go"}}
+	if !cfg.IsExcluded("pkg/api_generated.go") {
+		t.Error("IsExcluded didn't match a generated file by basename")
+	}
+	if cfg.IsExcluded("pkg/api.go") {
+		t.Error("IsExcluded matched a file that shouldn't be excluded")
+	}
+}
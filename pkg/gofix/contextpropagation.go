@@ -0,0 +1,333 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// ContextPropagationFixer threads a context.Context parameter upward
+// through a call chain, starting from Seeds: functions already updated
+// to accept context.Context as their first parameter (Apply only ever
+// reads a seed's signature, never rewrites it). Every local caller of a
+// seed - or of a function Apply itself just updated, which is what lets
+// this reach arbitrarily deep through a chain of intermediate functions
+// in a single Apply - gains a `ctx context.Context` first parameter and
+// passes ctx into the call it made.
+type ContextPropagationFixer struct {
+	// Seeds names functions that already accept context.Context first.
+	// A plain name ("Fetch") matches a local call; a dotted name
+	// ("client.Fetch") matches a qualified call into another package.
+	Seeds []string
+	// CrossPackage allows a qualified ("pkg.Func") seed to trigger
+	// propagation into its local caller. Off by default: a package
+	// boundary is usually a deliberate API surface, so by default
+	// propagation stops at the first caller of a cross-package seed
+	// rather than rewriting it too.
+	CrossPackage bool
+}
+
+// Apply threads context.Context through every caller in files - which
+// must all share fset and belong to a single package - that transitively
+// calls a Seed function, and reports whether it changed anything.
+func (fx ContextPropagationFixer) Apply(fset *token.FileSet, files []*ast.File) (changed bool, err error) {
+	funcDecls := map[string]*ast.FuncDecl{}
+	fileOfFunc := map[string]*ast.File{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			funcDecls[fn.Name.Name] = fn
+			fileOfFunc[fn.Name.Name] = file
+		}
+	}
+
+	// requiredArgs is, for each local function, the argument count a call
+	// to it needs to already be passing context: its original arity if it
+	// already took context from the start (like Fetch - any call with
+	// that many args already supplies one), or one more than its original
+	// arity otherwise (a call to a not-yet-fixed local function never has
+	// more than its original arg count, so anything at or below that is
+	// definitely still missing context). Captured up front, before any
+	// rewrite in this Apply.
+	requiredArgs := map[string]int{}
+	for name, fn := range funcDecls {
+		arity := paramCount(fn.Type.Params)
+		if hasContextParam(fn) {
+			requiredArgs[name] = arity
+		} else {
+			requiredArgs[name] = arity + 1
+		}
+	}
+
+	requiresContext := map[string]bool{}
+	for _, s := range fx.Seeds {
+		requiresContext[s] = true
+	}
+	for name, fn := range funcDecls {
+		if hasContextParam(fn) {
+			requiresContext[name] = true
+		}
+	}
+
+	// Fixed-point worklist: a caller gaining context in one round can
+	// itself be the call that forces its own caller to gain context in
+	// the next, which is how a three-level chain gets threaded all the
+	// way to the top in a single Apply.
+	toAdd := map[string]bool{}
+	for {
+		progressed := false
+		for name, fn := range funcDecls {
+			if requiresContext[name] || fn.Body == nil {
+				continue
+			}
+			if callsRequiringContext(fn, requiresContext, requiredArgs, fx.CrossPackage) {
+				requiresContext[name] = true
+				toAdd[name] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	if len(toAdd) == 0 {
+		return false, nil
+	}
+
+	for name := range toAdd {
+		fn := funcDecls[name]
+		addContextParam(fn)
+		astutil.AddImport(fset, fileOfFunc[name], "context")
+		changed = true
+	}
+	for name, fn := range funcDecls {
+		if requiresContext[name] && fn.Body != nil {
+			rewriteCallsToPassContext(fn, requiresContext, requiredArgs, ctxParamName(fn))
+		}
+	}
+	return changed, nil
+}
+
+// paramCount counts the parameters a field list declares, treating an
+// unnamed field as contributing exactly one.
+func paramCount(fl *ast.FieldList) int {
+	if fl == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range fl.List {
+		if len(f.Names) == 0 {
+			n++
+			continue
+		}
+		n += len(f.Names)
+	}
+	return n
+}
+
+// callAlreadyPassesContext reports whether call is already passing a
+// context argument to the function name identifies. For a local target,
+// that's known exactly from requiredArgs (see its construction in Apply).
+// For an external target (a cross-package seed this Apply never sees the
+// declaration of) it falls back to recognizing the common ctx-producing
+// shapes.
+func callAlreadyPassesContext(call *ast.CallExpr, name string, requiredArgs map[string]int) bool {
+	if arity, ok := requiredArgs[name]; ok {
+		return len(call.Args) >= arity
+	}
+	if len(call.Args) == 0 {
+		return false
+	}
+	switch arg := call.Args[0].(type) {
+	case *ast.Ident:
+		return arg.Name == "ctx"
+	case *ast.CallExpr:
+		sel, ok := arg.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		x, ok := sel.X.(*ast.Ident)
+		return ok && x.Name == "context" && (sel.Sel.Name == "Background" || sel.Sel.Name == "TODO")
+	default:
+		return false
+	}
+}
+
+// hasContextParam reports whether fn's first parameter is context.Context.
+func hasContextParam(fn *ast.FuncDecl) bool {
+	if fn.Type.Params == nil || len(fn.Type.Params.List) == 0 {
+		return false
+	}
+	return isContextType(fn.Type.Params.List[0].Type)
+}
+
+func isContextType(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == "context" && sel.Sel.Name == "Context"
+}
+
+// ctxParamName returns the name fn's existing context.Context parameter
+// was declared with, or "ctx" if fn doesn't have one yet.
+func ctxParamName(fn *ast.FuncDecl) string {
+	if hasContextParam(fn) && len(fn.Type.Params.List[0].Names) > 0 {
+		return fn.Type.Params.List[0].Names[0].Name
+	}
+	return "ctx"
+}
+
+// addContextParam prepends `ctx context.Context` to fn's parameter list.
+func addContextParam(fn *ast.FuncDecl) {
+	param := &ast.Field{
+		Names: []*ast.Ident{ast.NewIdent("ctx")},
+		Type:  &ast.SelectorExpr{X: ast.NewIdent("context"), Sel: ast.NewIdent("Context")},
+	}
+	if fn.Type.Params == nil {
+		fn.Type.Params = &ast.FieldList{}
+	}
+	fn.Type.Params.List = append([]*ast.Field{param}, fn.Type.Params.List...)
+}
+
+// calleeName returns the name a call expression's target is known by: the
+// plain name for a local call (Foo(...)), or the "pkg.Foo" qualified form
+// for a call through a selector (pkg.Foo(...)), along with whether that
+// form crosses a package boundary.
+func calleeName(expr ast.Expr) (name string, crossPackage, ok bool) {
+	switch f := expr.(type) {
+	case *ast.Ident:
+		return f.Name, false, true
+	case *ast.SelectorExpr:
+		if x, ok := f.X.(*ast.Ident); ok {
+			return x.Name + "." + f.Sel.Name, true, true
+		}
+	}
+	return "", false, false
+}
+
+// callsRequiringContext reports whether fn's body calls a function already
+// in requiresContext - skipping a cross-package call unless crossPackage
+// is enabled, per ContextPropagationFixer.CrossPackage's doc comment.
+func callsRequiringContext(fn *ast.FuncDecl, requiresContext map[string]bool, requiredArgs map[string]int, crossPackage bool) bool {
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name, cross, ok := calleeName(call.Fun)
+		if !ok || (cross && !crossPackage) {
+			return true
+		}
+		if requiresContext[name] && !callAlreadyPassesContext(call, name, requiredArgs) {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// rewriteCallsToPassContext prepends ctxName as the first argument of
+// every call in fn's body whose target is in requiresContext, unless it's
+// already being passed.
+func rewriteCallsToPassContext(fn *ast.FuncDecl, requiresContext map[string]bool, requiredArgs map[string]int, ctxName string) {
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name, _, ok := calleeName(call.Fun)
+		if !ok || !requiresContext[name] || callAlreadyPassesContext(call, name, requiredArgs) {
+			return true
+		}
+		call.Args = append([]ast.Expr{ast.NewIdent(ctxName)}, call.Args...)
+		return true
+	})
+}
+
+// RunContextPropagationPass runs ContextPropagationFixer over every file
+// in paths, which must all belong to the same package - propagating
+// context through a call chain requires seeing every caller at once, the
+// same reason RunNamingConventionPass type-checks the whole package
+// together. It's structured the same transactional way as that pass.
+func RunContextPropagationPass(paths []string, seeds []string, crossPackage bool) ([]patch.Result, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	files := map[string]bool{}
+	for _, p := range paths {
+		files[p] = true
+	}
+	txnFiles := make([]string, 0, len(files))
+	for f := range files {
+		txnFiles = append(txnFiles, f)
+	}
+	txn, err := patch.Begin(txnFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	before := make(map[string][]byte, len(txnFiles))
+	asts := make(map[string]*ast.File, len(txnFiles))
+	var pkgFiles []*ast.File
+	for _, file := range txnFiles {
+		src := txn.Read(file)
+		before[file] = src
+		fileAST, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		asts[file] = fileAST
+		pkgFiles = append(pkgFiles, fileAST)
+	}
+
+	fixer := ContextPropagationFixer{Seeds: seeds, CrossPackage: crossPackage}
+	if _, err := fixer.Apply(fset, pkgFiles); err != nil {
+		_ = txn.Rollback()
+		return nil, err
+	}
+
+	var results []patch.Result
+	for _, file := range txnFiles {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, asts[file]); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+		if bytes.Equal(patched, before[file]) {
+			continue
+		}
+
+		bs, be, as, ae := patch.DiffLines(before[file], patched)
+		txn.Write(file, patched)
+		results = append(results, patch.Result{
+			File: file, Kind: patch.KindContextPropagation, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
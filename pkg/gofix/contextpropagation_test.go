@@ -0,0 +1,133 @@
+package gofix
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestContextPropagationFixerThreadsThreeLevelChain(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+import "context"
+
+func Fetch(ctx context.Context, id string) (string, error) {
+	return id, nil
+}
+`,
+		"b.go": `package p
+
+func loadUser(id string) (string, error) {
+	return Fetch(id)
+}
+`,
+		"c.go": `package p
+
+func handleRequest(id string) (string, error) {
+	return loadUser(id)
+}
+`,
+	})
+
+	changed, err := (ContextPropagationFixer{Seeds: []string{"Fetch"}}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	out := renderAll(t, fset, files)
+	if !strings.Contains(out["b.go"], "func loadUser(ctx context.Context, id string) (string, error) {") {
+		t.Errorf("loadUser not given a ctx parameter:\n%s", out["b.go"])
+	}
+	if !strings.Contains(out["b.go"], "Fetch(ctx, id)") {
+		t.Errorf("loadUser doesn't pass ctx to Fetch:\n%s", out["b.go"])
+	}
+	if !strings.Contains(out["c.go"], "func handleRequest(ctx context.Context, id string) (string, error) {") {
+		t.Errorf("handleRequest (the deepest caller, two hops from the seed) not given a ctx parameter:\n%s", out["c.go"])
+	}
+	if !strings.Contains(out["c.go"], "loadUser(ctx, id)") {
+		t.Errorf("handleRequest doesn't pass ctx to loadUser:\n%s", out["c.go"])
+	}
+}
+
+func TestContextPropagationFixerStopsAtPackageBoundaryByDefault(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+import "other/client"
+
+func loadUser(id string) (string, error) {
+	return client.Fetch(id)
+}
+`,
+	})
+
+	changed, err := (ContextPropagationFixer{Seeds: []string{"client.Fetch"}}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: client.Fetch is a cross-package seed and CrossPackage is false")
+	}
+}
+
+func TestContextPropagationFixerCrossesPackageBoundaryWhenEnabled(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+import "other/client"
+
+func loadUser(id string) (string, error) {
+	return client.Fetch(id)
+}
+`,
+	})
+
+	changed, err := (ContextPropagationFixer{Seeds: []string{"client.Fetch"}, CrossPackage: true}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one: CrossPackage is true")
+	}
+
+	out := renderAll(t, fset, files)
+	if !strings.Contains(out["a.go"], "func loadUser(ctx context.Context, id string) (string, error) {") {
+		t.Errorf("loadUser not given a ctx parameter:\n%s", out["a.go"])
+	}
+	if !strings.Contains(out["a.go"], "client.Fetch(ctx, id)") {
+		t.Errorf("loadUser doesn't pass ctx to client.Fetch:\n%s", out["a.go"])
+	}
+}
+
+func TestContextPropagationFixerLeavesUnrelatedFunctionsAlone(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+import "context"
+
+func Fetch(ctx context.Context, id string) (string, error) {
+	return id, nil
+}
+
+func unrelated() int {
+	return 42
+}
+`,
+	})
+
+	changed, err := (ContextPropagationFixer{Seeds: []string{"Fetch"}}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: nothing calls Fetch")
+	}
+}
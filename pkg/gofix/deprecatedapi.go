@@ -0,0 +1,174 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// deprecatedAPICallRules is the static table of call-shaped migrations
+// DeprecatedAPIFixer applies unconditionally, reusing SSRFixer's
+// call-rewrite engine: io/ioutil's file and stream helpers moved to os/io
+// in Go 1.16, and a representative handful of syscall functions that
+// moved to golang.org/x/sys/unix for the cross-platform parity syscall
+// itself no longer tracks. It is not an exhaustive syscall migration -
+// callers with a broader syscall surface should add their own rules via
+// Config.SSRRules instead.
+var deprecatedAPICallRules = []SSRRule{
+	{Pattern: "ioutil.ReadFile($a)", Replacement: "os.ReadFile($a)", PatternImport: "io/ioutil", ReplacementImport: "os"},
+	{Pattern: "ioutil.WriteFile($a, $b, $c)", Replacement: "os.WriteFile($a, $b, $c)", PatternImport: "io/ioutil", ReplacementImport: "os"},
+	{Pattern: "ioutil.ReadAll($a)", Replacement: "io.ReadAll($a)", PatternImport: "io/ioutil", ReplacementImport: "io"},
+	{Pattern: "ioutil.ReadDir($a)", Replacement: "os.ReadDir($a)", PatternImport: "io/ioutil", ReplacementImport: "os"},
+	{Pattern: "ioutil.TempDir($a, $b)", Replacement: "os.MkdirTemp($a, $b)", PatternImport: "io/ioutil", ReplacementImport: "os"},
+	{Pattern: "ioutil.TempFile($a, $b)", Replacement: "os.CreateTemp($a, $b)", PatternImport: "io/ioutil", ReplacementImport: "os"},
+	{Pattern: "ioutil.NopCloser($a)", Replacement: "io.NopCloser($a)", PatternImport: "io/ioutil", ReplacementImport: "io"},
+	{Pattern: "syscall.Mmap($a, $b, $c, $d, $e)", Replacement: "unix.Mmap($a, $b, $c, $d, $e)", PatternImport: "syscall", ReplacementImport: "golang.org/x/sys/unix"},
+	{Pattern: "syscall.Munmap($a)", Replacement: "unix.Munmap($a)", PatternImport: "syscall", ReplacementImport: "golang.org/x/sys/unix"},
+	{Pattern: "syscall.Kill($a, $b)", Replacement: "unix.Kill($a, $b)", PatternImport: "syscall", ReplacementImport: "golang.org/x/sys/unix"},
+}
+
+// deprecatedAPIConstMigration is one bare-selector migration (a constant
+// or variable reference, e.g. "ioutil.Discard" or "os.SEEK_SET") - the
+// shape SSRRule's call-only pattern grammar can't express.
+type deprecatedAPIConstMigration struct {
+	OldPkg, OldName   string
+	NewPkg, NewName   string
+	PatternImport     string
+	ReplacementImport string
+}
+
+var deprecatedAPIConstRules = []deprecatedAPIConstMigration{
+	{OldPkg: "ioutil", OldName: "Discard", NewPkg: "io", NewName: "Discard", PatternImport: "io/ioutil", ReplacementImport: "io"},
+	{OldPkg: "os", OldName: "SEEK_SET", NewPkg: "io", NewName: "SeekStart", PatternImport: "os", ReplacementImport: "io"},
+	{OldPkg: "os", OldName: "SEEK_CUR", NewPkg: "io", NewName: "SeekCurrent", PatternImport: "os", ReplacementImport: "io"},
+	{OldPkg: "os", OldName: "SEEK_END", NewPkg: "io", NewName: "SeekEnd", PatternImport: "os", ReplacementImport: "io"},
+}
+
+// DeprecatedAPIFixer rewrites every deprecated call or constant reference
+// in deprecatedAPICallRules/deprecatedAPIConstRules to its modern
+// equivalent. Like SSRFixer (which it delegates its call-shaped rules to)
+// it runs unconditionally over every file in scope rather than reacting to
+// a compiler diagnostic, so it isn't wired through the Fixer/Registry
+// pipeline; RunDeprecatedAPIPass drives it directly. Its migration table
+// is static and not user-configurable - a project that wants to add its
+// own API migrations should use Config.SSRRules instead.
+type DeprecatedAPIFixer struct{}
+
+// Apply rewrites file in place and reports whether it changed anything.
+// Each rule manages its own import: AddImport is a no-op if the
+// replacement is already imported (the dedup the caller-facing migration
+// table promises), and the old import is only removed once no reference
+// to it - from this fixer's rules or otherwise - remains.
+func (fx DeprecatedAPIFixer) Apply(fset *token.FileSet, file *ast.File) (bool, error) {
+	changed, err := (SSRFixer{Rules: deprecatedAPICallRules}).Apply(fset, file)
+	if err != nil {
+		return changed, err
+	}
+
+	for _, rule := range deprecatedAPIConstRules {
+		matched := false
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkgIdent, ok := sel.X.(*ast.Ident)
+			if !ok || pkgIdent.Name != rule.OldPkg || sel.Sel.Name != rule.OldName {
+				return true
+			}
+			if rule.PatternImport != "" && !importedAs(file, rule.PatternImport, rule.OldPkg) {
+				return true
+			}
+			pkgIdent.Name = rule.NewPkg
+			sel.Sel.Name = rule.NewName
+			matched = true
+			return true
+		})
+		if !matched {
+			continue
+		}
+		changed = true
+
+		if rule.ReplacementImport != "" {
+			astutil.AddImport(fset, file, rule.ReplacementImport)
+		}
+		if rule.PatternImport != "" && !referencesIdent(file, rule.OldPkg) {
+			astutil.DeleteImport(fset, file, rule.PatternImport)
+		}
+	}
+	return changed, nil
+}
+
+// RunDeprecatedAPIPass runs DeprecatedAPIFixer over each file in paths,
+// writing back any file it changes. It's structured the same
+// transactional way as RunSSRPass/RunUnusedPass/RunPluginPass: every file
+// in a run is staged in a single patch.Transaction and only committed once
+// the whole pass succeeds.
+func RunDeprecatedAPIPass(paths []string) ([]patch.Result, error) {
+	files := map[string]bool{}
+	for _, p := range paths {
+		files[p] = true
+	}
+	txnFiles := make([]string, 0, len(files))
+	for f := range files {
+		txnFiles = append(txnFiles, f)
+	}
+	txn, err := patch.Begin(txnFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []patch.Result
+	fixer := DeprecatedAPIFixer{}
+	for file := range files {
+		before := txn.Read(file)
+
+		fset := token.NewFileSet()
+		fileAST, err := parser.ParseFile(fset, file, before, parser.ParseComments)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+
+		changed, err := fixer.Apply(fset, fileAST)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+
+		if err := FormatImports(fset, fileAST, modulePathOf(filepath.Dir(file))); err != nil {
+			_ = txn.Rollback()
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, fileAST); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+
+		bs, be, as, ae := patch.DiffLines(before, patched)
+		txn.Write(file, patched)
+		results = append(results, patch.Result{
+			File: file, Kind: patch.KindSSR, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,121 @@
+package gofix
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedAPIFixerMigratesIoutilCallsAndConstants(t *testing.T) {
+	const src = `package p
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+func run() {
+	b, _ := ioutil.ReadFile("x.txt")
+	_ = ioutil.WriteFile("y.txt", b, 0644)
+	io.Copy(ioutil.Discard, nil)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (DeprecatedAPIFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{`os.ReadFile("x.txt")`, `os.WriteFile("y.txt"`, "io.Discard"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got:\n%s\nwant %q", out, want)
+		}
+	}
+	if strings.Contains(out, "ioutil") {
+		t.Errorf("got:\n%s\nwant no remaining reference to ioutil", out)
+	}
+}
+
+func TestDeprecatedAPIFixerMigratesSeekConstants(t *testing.T) {
+	const src = `package p
+
+import "os"
+
+func run(f *os.File) {
+	f.Seek(0, os.SEEK_SET)
+	f.Seek(0, os.SEEK_CUR)
+	f.Seek(0, os.SEEK_END)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (DeprecatedAPIFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"io.SeekStart", "io.SeekCurrent", "io.SeekEnd"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got:\n%s\nwant %q", out, want)
+		}
+	}
+	if strings.Contains(out, "SEEK_") {
+		t.Errorf("got:\n%s\nwant no remaining os.SEEK_* reference", out)
+	}
+	if !strings.Contains(out, `"os"`) {
+		t.Errorf("got:\n%s\nwant the os import kept, since f *os.File still references it", out)
+	}
+}
+
+func TestDeprecatedAPIFixerIgnoresUnrelatedCalls(t *testing.T) {
+	const src = `package p
+
+import "fmt"
+
+func run() {
+	fmt.Println("hi")
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (DeprecatedAPIFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none")
+	}
+}
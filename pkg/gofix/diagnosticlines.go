@@ -0,0 +1,61 @@
+package gofix
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// LineDiagnosticSource reads `file:line:col: message` diagnostic lines -
+// the format `go build`, `go vet`, and most Go linters (golangci-lint,
+// staticcheck, revive, ...) all emit - from Reader, typically a CI job's
+// captured output piped into the patcher's stdin. See ParseDiagnosticLines
+// for the parsing rules.
+type LineDiagnosticSource struct {
+	Reader io.Reader
+}
+
+// Diagnostics implements DiagnosticSource.
+func (s LineDiagnosticSource) Diagnostics() ([]Diagnostic, error) {
+	return ParseDiagnosticLines(s.Reader)
+}
+
+// diagnosticLineColRE matches "file:line:col: message", the shape most Go
+// tools use. The column group is optional so a tool that only reports a
+// line number (e.g. some golangci-lint linters) still matches.
+var diagnosticLineColRE = regexp.MustCompile(`^(?P<file>[^:\s][^:]*):(?P<line>\d+):(?:(?P<col>\d+):)?\s*(?P<msg>.+)$`)
+
+// ParseDiagnosticLines reads r line by line and parses every line matching
+// `file:line[:col]: message` into a Diagnostic. Lines that don't match -
+// blank lines, "# package/path" build headers, a linter's summary line,
+// anything else - are skipped rather than treated as an error, and a line
+// that's merely missing its column number is still parsed; only a read
+// error from r is returned. It never panics on malformed input.
+func ParseDiagnosticLines(r io.Reader) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "vet: ")
+
+		m := diagnosticLineColRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		diags = append(diags, Diagnostic{
+			File:    m[1],
+			Line:    atoiOrZero(m[2]),
+			Col:     atoiOrZero(m[3]),
+			Message: m[4],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return diags, err
+	}
+	return diags, nil
+}
@@ -0,0 +1,117 @@
+package gofix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDiagnosticLines(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Diagnostic
+	}{
+		{
+			name: "go build",
+			line: `main.go:12:5: undefined: fmt`,
+			want: Diagnostic{File: "main.go", Line: 12, Col: 5, Message: "undefined: fmt"},
+		},
+		{
+			name: "go vet",
+			line: `vet: ./main.go:7:2: unreachable code`,
+			want: Diagnostic{File: "./main.go", Line: 7, Col: 2, Message: "unreachable code"},
+		},
+		{
+			name: "golangci-lint (line-number format, with linter name)",
+			line: `pkg/foo.go:40:10: Error return value is not checked (errcheck)`,
+			want: Diagnostic{File: "pkg/foo.go", Line: 40, Col: 10, Message: "Error return value is not checked (errcheck)"},
+		},
+		{
+			name: "golangci-lint without a column",
+			line: `pkg/foo.go:40: exported function Foo should have comment (golint)`,
+			want: Diagnostic{File: "pkg/foo.go", Line: 40, Col: 0, Message: "exported function Foo should have comment (golint)"},
+		},
+		{
+			name: "staticcheck",
+			line: `internal/scan.go:88:6: this value of err is never used (SA4006)`,
+			want: Diagnostic{File: "internal/scan.go", Line: 88, Col: 6, Message: "this value of err is never used (SA4006)"},
+		},
+		{
+			name: "errcheck with a tab before the message",
+			line: "server.go:101:2:\tio.Copy(w, r)",
+			want: Diagnostic{File: "server.go", Line: 101, Col: 2, Message: "io.Copy(w, r)"},
+		},
+		{
+			name: "revive",
+			line: `handler.go:15:1: exported: exported function Handle should have comment or be unexported`,
+			want: Diagnostic{File: "handler.go", Line: 15, Col: 1, Message: "exported: exported function Handle should have comment or be unexported"},
+		},
+		{
+			name: "ineffassign",
+			line: `cache.go:22:2: ineffectual assignment to err`,
+			want: Diagnostic{File: "cache.go", Line: 22, Col: 2, Message: "ineffectual assignment to err"},
+		},
+		{
+			name: "gosimple",
+			line: `util.go:9:1: should omit type bool from declaration; it will be inferred from the right-hand side (S1021)`,
+			want: Diagnostic{File: "util.go", Line: 9, Col: 1, Message: "should omit type bool from declaration; it will be inferred from the right-hand side (S1021)"},
+		},
+		{
+			name: "nested path with a column-less line",
+			line: `cmd/repo-patcher/main.go:30: line is 145 characters (lll)`,
+			want: Diagnostic{File: "cmd/repo-patcher/main.go", Line: 30, Col: 0, Message: "line is 145 characters (lll)"},
+		},
+		{
+			name: "unconventionalpath with go vet's leading relative dot",
+			line: `./pkg/gofix/classifier.go:200:10: "strings" imported and not used`,
+			want: Diagnostic{File: "./pkg/gofix/classifier.go", Line: 200, Col: 10, Message: `"strings" imported and not used`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags, err := ParseDiagnosticLines(strings.NewReader(tt.line))
+			if err != nil {
+				t.Fatalf("ParseDiagnosticLines: %v", err)
+			}
+			if len(diags) != 1 {
+				t.Fatalf("got %d diagnostics, want 1: %+v", len(diags), diags)
+			}
+			if diags[0] != tt.want {
+				t.Errorf("got %+v, want %+v", diags[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiagnosticLinesSkipsNonDiagnosticLines(t *testing.T) {
+	const input = `# github.com/example/pkg
+go: downloading github.com/example/dep v1.0.0
+
+main.go:3:1: undefined: fmt
+3 issues found.
+`
+	diags, err := ParseDiagnosticLines(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseDiagnosticLines: %v", err)
+	}
+	if len(diags) != 1 || diags[0].File != "main.go" {
+		t.Fatalf("got %+v, want exactly the main.go diagnostic", diags)
+	}
+}
+
+func TestParseDiagnosticLinesNeverPanicsOnMalformedInput(t *testing.T) {
+	inputs := []string{
+		"",
+		":::",
+		"not a diagnostic at all",
+		"file.go::: bad numbers",
+		"file.go:notanumber:5: message",
+		"\x00\x01\x02 binary garbage",
+	}
+	for _, in := range inputs {
+		if _, err := ParseDiagnosticLines(strings.NewReader(in)); err != nil {
+			t.Errorf("ParseDiagnosticLines(%q) returned error: %v", in, err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// ErrorReturnFixer is the built-in Fixer for CategoryErrorReturnMismatch:
+// the compiler's "assignment mismatch: 1 variable but f returns 2 values"
+// error, which is what `x := f()` produces when f actually returns
+// (T, error) and the caller only bound the first value. It rewrites the
+// assignment to capture both values - naming the second "err" - and
+// inserts an `if err != nil { return ... }` guard immediately after it.
+//
+// The guard's return values come from the *enclosing* function's own
+// signature, not f's: the diagnostic already establishes f returns
+// exactly two values, so nothing further about f needs resolving. The
+// enclosing function's non-error return types are zeroed with basic
+// literals (string/bool/numeric) or nil (error); anything else - a
+// struct, pointer, slice, map, or other named type - can't be zeroed
+// soundly without go/types, so the fixer reports no change rather than
+// guess. The fixer also requires the enclosing function's last return
+// value to be of type error; otherwise there's no return slot to
+// propagate err through.
+type ErrorReturnFixer struct{}
+
+func init() {
+	DefaultRegistry.Register("error-return-mismatch", ErrorReturnFixer{})
+}
+
+func (ErrorReturnFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryErrorReturnMismatch
+}
+
+func (ErrorReturnFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fn := enclosingFunc(fset, file, diag.Line)
+	if fn == nil {
+		return false, nil
+	}
+
+	zeros, ok := errorReturnZeros(fn)
+	if !ok {
+		return false, nil
+	}
+
+	// Match by position >= diag.Line rather than ==: when a file has
+	// several of these diagnostics, fixing an earlier one inserts lines
+	// and pushes everything after it down, so a later diagnostic's
+	// original line number no longer points at its statement by the time
+	// this fixer runs again. The first still-unconverted candidate at or
+	// after that line is always the right one, since fixed occurrences no
+	// longer have a single-name Lhs and drop out of the match.
+	var target *ast.AssignStmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if target != nil {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		if _, ok := assign.Rhs[0].(*ast.CallExpr); !ok || fset.Position(assign.Pos()).Line < diag.Line {
+			return true
+		}
+		target = assign
+		return false
+	})
+	if target == nil {
+		return false, nil
+	}
+
+	target.Lhs = append(target.Lhs, ast.NewIdent("err"))
+
+	results := append(append([]ast.Expr{}, zeros...), ast.NewIdent("err"))
+	guard := &ast.IfStmt{
+		Cond: &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: results}}},
+	}
+
+	inserted := false
+	astutil.Apply(fn.Body, nil, func(c *astutil.Cursor) bool {
+		if inserted {
+			return false
+		}
+		if assign, ok := c.Node().(*ast.AssignStmt); ok && assign == target {
+			c.InsertAfter(guard)
+			inserted = true
+			return false
+		}
+		return true
+	})
+	return inserted, nil
+}
+
+// enclosingFunc returns the top-level function or method declaration whose
+// body spans line, or nil if none does.
+func enclosingFunc(fset *token.FileSet, file *ast.File, line int) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		start, end := fset.Position(fn.Pos()).Line, fset.Position(fn.End()).Line
+		if line >= start && line <= end {
+			return fn
+		}
+	}
+	return nil
+}
+
+// errorReturnZeros reports whether fn's last return value is of type
+// error, and if so returns the zero-value expressions for every return
+// value before it. It returns ok=false if fn doesn't end in an error
+// return, or if any of its other return types isn't a basic builtin kind
+// this package knows how to zero.
+func errorReturnZeros(fn *ast.FuncDecl) (zeros []ast.Expr, ok bool) {
+	if fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+		return nil, false
+	}
+
+	var types []ast.Expr
+	for _, field := range fn.Type.Results.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, field.Type)
+		}
+	}
+
+	last, ok := types[len(types)-1].(*ast.Ident)
+	if !ok || last.Name != "error" {
+		return nil, false
+	}
+
+	zeros = make([]ast.Expr, 0, len(types)-1)
+	for _, t := range types[:len(types)-1] {
+		zero, ok := basicZeroValue(t)
+		if !ok {
+			return nil, false
+		}
+		zeros = append(zeros, zero)
+	}
+	return zeros, true
+}
+
+// ProposeErrorReturnFix resolves a CategoryErrorReturnMismatch Fix through
+// ErrorReturnFixer and returns the file's source with the fix applied,
+// plus the patch.Result describing the edit. It returns a nil results
+// slice if fix isn't an error-return-mismatch fix or the Fixer reports no
+// change (e.g. the enclosing function doesn't end in an error return).
+func ProposeErrorReturnFix(src []byte, fix Fix) ([]byte, []patch.Result, error) {
+	if fix.Category != CategoryErrorReturnMismatch {
+		return nil, nil, nil
+	}
+
+	fset := token.NewFileSet()
+	fileAST, err := parser.ParseFile(fset, fix.Diagnostic.File, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	changed, err := (ErrorReturnFixer{}).Apply(fset, fileAST, fix.Diagnostic)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !changed {
+		return nil, nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, fileAST); err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+	out := buf.Bytes()
+
+	bs, be, as, ae := patch.DiffLines(src, out)
+	result := patch.Result{
+		File: fix.Diagnostic.File, Kind: patch.KindReplaceCall, Changed: true,
+		BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+	}
+	return out, []patch.Result{result}, nil
+}
+
+// basicZeroValue returns the zero-value literal for t if t is a builtin
+// string, bool, or numeric identifier, and false otherwise.
+func basicZeroValue(t ast.Expr) (ast.Expr, bool) {
+	ident, ok := t.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	switch ident.Name {
+	case "string":
+		return &ast.BasicLit{Kind: token.STRING, Value: `""`}, true
+	case "bool":
+		return ast.NewIdent("false"), true
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune",
+		"float32", "float64":
+		return &ast.BasicLit{Kind: token.INT, Value: "0"}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,103 @@
+package gofix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"plugin"
+	"strings"
+)
+
+// Fixer is a single deterministic rewrite rule, triggered by a Diagnostic
+// and applied directly to its file's AST. It's the extension point for
+// rules this package doesn't ship with - a deprecated internal package
+// that should always become its replacement, an API call shape that needs
+// rewriting - without requiring access to gofix's internal SymbolIndex.
+//
+// fset is threaded alongside file because several useful edits (adding or
+// removing an import, the way astedit.AddImport does) need it to locate
+// and merge source positions; a Fixer that never touches imports can
+// ignore it.
+type Fixer interface {
+	// Applies reports whether this Fixer can handle diag.
+	Applies(diag Diagnostic) bool
+	// Apply rewrites file in place for diag, reporting whether it changed
+	// anything. Callers are responsible for reformatting file back to
+	// source once every applicable Fixer has run.
+	Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (changed bool, err error)
+}
+
+// Registry holds the set of Fixers consulted for a diagnostic, keyed by
+// name so a later Register call can deliberately override an earlier one
+// (e.g. a plugin replacing a built-in).
+type Registry struct {
+	fixers map[string]Fixer
+	order  []string
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{fixers: map[string]Fixer{}}
+}
+
+// Register adds f under name, consulted in registration order by For.
+// Registering the same name twice replaces the earlier Fixer in place,
+// preserving its original position.
+func (r *Registry) Register(name string, f Fixer) {
+	if _, exists := r.fixers[name]; !exists {
+		r.order = append(r.order, name)
+	}
+	r.fixers[name] = f
+}
+
+// For returns every registered Fixer, in registration order, whose
+// Applies(diag) reports true.
+func (r *Registry) For(diag Diagnostic) []Fixer {
+	var matched []Fixer
+	for _, name := range r.order {
+		if f := r.fixers[name]; f.Applies(diag) {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+// Has reports whether name is registered.
+func (r *Registry) Has(name string) bool {
+	_, ok := r.fixers[name]
+	return ok
+}
+
+// DefaultRegistry is the package-wide Registry that built-in Fixers not
+// requiring per-run state register into at init time, and that LoadPlugins
+// adds .so-sourced Fixers to.
+var DefaultRegistry = NewRegistry()
+
+// LoadPlugins opens every .so file in paths via plugin.Open and registers
+// its exported Fixer into reg. Each plugin must export a package-level
+// variable named Fixer of type gofix.Fixer; it's registered under the
+// plugin's base filename with the .so extension stripped.
+//
+// Go plugins only build and load on linux/darwin/freebsd with cgo enabled
+// (see `go help buildmode`); on unsupported platforms plugin.Open returns
+// an error, same as it would for a missing or malformed .so.
+func LoadPlugins(reg *Registry, paths []string) error {
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return fmt.Errorf("gofix: open plugin %s: %w", path, err)
+		}
+		sym, err := p.Lookup("Fixer")
+		if err != nil {
+			return fmt.Errorf("gofix: plugin %s: %w", path, err)
+		}
+		f, ok := sym.(*Fixer)
+		if !ok {
+			return fmt.Errorf("gofix: plugin %s: exported Fixer is not a gofix.Fixer", path)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		reg.Register(name, *f)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package gofix_test
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// deleteUnusedVarFixer stands in for a .so-loaded plugin: instead of the
+// built-in unused-var fix's blank-assign strategy, it deletes the
+// offending declaration's statement outright.
+type deleteUnusedVarFixer struct{}
+
+func (deleteUnusedVarFixer) Applies(diag gofix.Diagnostic) bool {
+	return gofix.Classify(diag).Category == gofix.CategoryUnusedVar
+}
+
+func (deleteUnusedVarFixer) Apply(fset *token.FileSet, file *ast.File, diag gofix.Diagnostic) (bool, error) {
+	name := gofix.Classify(diag).Symbol
+	deleted := false
+	astutil.Apply(file, nil, func(c *astutil.Cursor) bool {
+		if deleted {
+			return false
+		}
+		assign, ok := c.Node().(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+				c.Delete()
+				deleted = true
+				return false
+			}
+		}
+		return true
+	})
+	return deleted, nil
+}
+
+// TestRunPluginPassAppliesRegisteredFixer exercises RunPluginPass with a
+// Registry holding only a stub Fixer - standing in for a .repopatcher.yaml
+// -loaded plugin, since building and loading a real .so is out of reach
+// for a portable unit test - and checks it ran instead of any built-in
+// pass, using a strategy RunUnusedPass doesn't offer.
+func TestRunPluginPassAppliesRegisteredFixer(t *testing.T) {
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join("testdata", "plugin_pass"), workDir); err != nil {
+		t.Fatalf("copy testdata: %v", err)
+	}
+
+	reg := gofix.NewRegistry()
+	reg.Register("delete-unused-var", deleteUnusedVarFixer{})
+
+	results, err := gofix.RunPluginPass([]string{filepath.Join(workDir, "greet.go")}, reg)
+	if err != nil {
+		t.Fatalf("RunPluginPass: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if got := results[0].Kind; got != patch.KindPluginFix {
+		t.Errorf("Kind = %v, want %v", got, patch.KindPluginFix)
+	}
+	if !results[0].Changed {
+		t.Error("Changed = false, want true")
+	}
+
+	out, err := os.ReadFile(filepath.Join(workDir, "greet.go"))
+	if err != nil {
+		t.Fatalf("read patched file: %v", err)
+	}
+	if strings.Contains(string(out), `"unused"`) {
+		t.Errorf("patched file still declares msg:\n%s", out)
+	}
+}
+
+// TestRegistryRegisterPreservesOrderOnOverride checks that re-registering
+// a name keeps its original slot in For's iteration order rather than
+// moving it to the end, so a plugin that overrides a built-in doesn't
+// silently change which Fixer runs first when several match.
+func TestRegistryRegisterPreservesOrderOnOverride(t *testing.T) {
+	var seen []string
+	record := func(name string) gofix.Fixer {
+		return recordingFixer{name: name, seen: &seen}
+	}
+
+	reg := gofix.NewRegistry()
+	reg.Register("a", record("a"))
+	reg.Register("b", record("b"))
+	reg.Register("a", record("a-overridden"))
+
+	diag := gofix.Diagnostic{Message: "x declared and not used"}
+	for _, f := range reg.For(diag) {
+		if _, err := f.Apply(nil, nil, diag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"a-overridden", "b"}
+	if len(seen) != len(want) || seen[0] != want[0] || seen[1] != want[1] {
+		t.Errorf("For order = %v, want %v", seen, want)
+	}
+}
+
+type recordingFixer struct {
+	name string
+	seen *[]string
+}
+
+func (f recordingFixer) Applies(gofix.Diagnostic) bool { return true }
+
+func (f recordingFixer) Apply(*token.FileSet, *ast.File, gofix.Diagnostic) (bool, error) {
+	*f.seen = append(*f.seen, f.name)
+	return false, nil
+}
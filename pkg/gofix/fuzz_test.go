@@ -0,0 +1,153 @@
+package gofix_test
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+// seedGoFiles adds every .go file under root (normally the scenarios/
+// tree) to f's corpus, in addition to whatever f.Add calls the fuzz
+// target itself makes for the empty-input/malformed-input edge cases a
+// directory walk won't think to cover. Go's fuzzing engine separately
+// persists any new crasher or interesting input it discovers under
+// testdata/fuzz/<FuzzName>/ the first time `go test -fuzz=<FuzzName>` is
+// run locally - that directory doesn't exist until then, and is what the
+// Makefile's fuzz target (and CI, if it archives testdata/) is seeding
+// for next time.
+func seedGoFiles(f *testing.F, root string) {
+	f.Helper()
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		f.Add(string(content))
+		return nil
+	})
+}
+
+// rewriteAddImport parses src and adds importPath to it via
+// astutil.AddImport, the same rewrite every missing-import Fixer in this
+// package performs, then reformats. A parse failure is returned, not
+// panicked - the overwhelming majority of fuzz-generated inputs won't be
+// valid Go source at all.
+func rewriteAddImport(src []byte, importPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "fuzz.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	astutil.AddImport(fset, file, importPath)
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rewriteRemoveImport is rewriteAddImport's counterpart for
+// astutil.DeleteImport, the rewrite every unused-import Fixer performs.
+func rewriteRemoveImport(src []byte, importPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "fuzz.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	astutil.DeleteImport(fset, file, importPath)
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FuzzRewriteAddImport feeds arbitrary byte strings as "Go source" through
+// the same parse/astutil.AddImport/format path every missing-import Fixer
+// in this package runs, and fails if it panics or produces invalid UTF-8.
+// Most inputs - partially written files, BOM-prefixed files, non-UTF-8
+// byte soup - won't parse as Go at all; a parse error for those is
+// expected and ignored, not a failure.
+func FuzzRewriteAddImport(f *testing.F) {
+	f.Add("package main\n\nfunc F() { fmt.Println(\"hi\") }\n")
+	f.Add("")
+	f.Add("\xef\xbb\xbfpackage main\n")
+	f.Add("package main\n\n// unterminated comment")
+	seedGoFiles(f, "../../scenarios")
+
+	f.Fuzz(func(t *testing.T, src string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("rewriteAddImport panicked on input %q: %v", src, r)
+			}
+		}()
+		out, err := rewriteAddImport([]byte(src), "fmt")
+		if err != nil {
+			return
+		}
+		if !utf8.Valid(out) {
+			t.Errorf("rewriteAddImport produced invalid UTF-8 for input %q: %q", src, out)
+		}
+	})
+}
+
+// FuzzRewriteRemoveImport is FuzzRewriteAddImport's counterpart for
+// astutil.DeleteImport.
+func FuzzRewriteRemoveImport(f *testing.F) {
+	f.Add("package main\n\nimport \"fmt\"\n\nfunc F() { fmt.Println(\"hi\") }\n")
+	f.Add("")
+	f.Add("\xef\xbb\xbfpackage main\n")
+	f.Add("package main\n\nimport (\n")
+	seedGoFiles(f, "../../scenarios")
+
+	f.Fuzz(func(t *testing.T, src string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("rewriteRemoveImport panicked on input %q: %v", src, r)
+			}
+		}()
+		out, err := rewriteRemoveImport([]byte(src), "fmt")
+		if err != nil {
+			return
+		}
+		if !utf8.Valid(out) {
+			t.Errorf("rewriteRemoveImport produced invalid UTF-8 for input %q: %q", src, out)
+		}
+	})
+}
+
+// FuzzParseDiagnosticLines feeds arbitrary text to ParseDiagnosticLines,
+// which already documents that it never panics on malformed input - this
+// target exists to hold it to that promise as the line-matching regex
+// evolves.
+func FuzzParseDiagnosticLines(f *testing.F) {
+	f.Add("x.go:3:2: undefined: fmt\n")
+	f.Add("")
+	f.Add("not a diagnostic line at all")
+	f.Add("# github.com/example/pkg\nx.go:1: some error\n")
+	seedGoFiles(f, "../../scenarios")
+
+	f.Fuzz(func(t *testing.T, src string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("ParseDiagnosticLines panicked on input %q: %v", src, r)
+			}
+		}()
+		// A read error (e.g. bufio.ErrTooLong on a pathologically long
+		// line) is a legitimate outcome, not a failure - only a panic is.
+		_, _ = gofix.ParseDiagnosticLines(strings.NewReader(src))
+	})
+}
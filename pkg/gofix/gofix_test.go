@@ -0,0 +1,287 @@
+package gofix_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// TestRunUnusedPassLeavesDiskUntouchedOnFailure exercises
+// testdata/unused_pass_failure, a package where the only unused-var
+// diagnostic go vet reports has no astedit fix. RunUnusedPass's
+// patch.Transaction must never flush a write for a fix that never
+// resolved, so the package should come out of a failed pass byte-for-byte
+// identical to how it went in.
+func TestRunUnusedPassLeavesDiskUntouchedOnFailure(t *testing.T) {
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join("testdata", "unused_pass_failure"), workDir); err != nil {
+		t.Fatalf("copy testdata: %v", err)
+	}
+
+	before := snapshot(t, workDir)
+
+	_, err := gofix.RunUnusedPass([]string{filepath.Join(workDir, "sum_test.go")}, gofix.RunOptions{Mode: gofix.UnusedRemove})
+	if err == nil {
+		t.Fatal("expected RunUnusedPass to fail on the range-clause variable")
+	}
+
+	after := snapshot(t, workDir)
+	for path, want := range before {
+		if got := after[path]; got != want {
+			t.Errorf("%s was modified despite the pass failing:\nbefore: %q\nafter:  %q", path, want, got)
+		}
+	}
+}
+
+// TestRunUnusedPassDryRun exercises scenarios/E007_go_dryrun: with
+// DryRun set, RunUnusedPass must report the same fix it would otherwise
+// apply, as a unified diff in Result.DiffOutput, while leaving the
+// package on disk untouched.
+func TestRunUnusedPassDryRun(t *testing.T) {
+	scenarioDir := filepath.Join("..", "..", "scenarios", "E007_go_dryrun")
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join(scenarioDir, "repo"), workDir); err != nil {
+		t.Fatalf("copy scenario repo: %v", err)
+	}
+
+	before := snapshot(t, workDir)
+
+	results, err := gofix.RunUnusedPass([]string{filepath.Join(workDir, "greet_test.go")}, gofix.RunOptions{Mode: gofix.UnusedRemove, DryRun: true})
+	if err != nil {
+		t.Fatalf("RunUnusedPass: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	want, err := os.ReadFile(filepath.Join(scenarioDir, "expected_fix", "greet_test.go.diff"))
+	if err != nil {
+		t.Fatalf("read golden diff: %v", err)
+	}
+	if got := results[0].DiffOutput; got != string(want) {
+		t.Errorf("DiffOutput mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	after := snapshot(t, workDir)
+	for path, want := range before {
+		if got := after[path]; got != want {
+			t.Errorf("%s was modified despite DryRun: true", path)
+		}
+	}
+}
+
+// TestRunUnusedPassCachesAppliedFix exercises scenarios/E001_go_unused_import_multi
+// with RunOptions.CachePath set: after the pass, the cache on disk must
+// hold an entry for the fixed file keyed by its original (broken) content,
+// and that entry's Patched bytes must match what actually landed on disk.
+func TestRunUnusedPassCachesAppliedFix(t *testing.T) {
+	scenarioDir := filepath.Join("..", "..", "scenarios", "E001_go_unused_import_multi")
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join(scenarioDir, "repo"), workDir); err != nil {
+		t.Fatalf("copy scenario repo: %v", err)
+	}
+
+	file := filepath.Join(workDir, "strutil_test.go")
+	before, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read %s: %v", file, err)
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	if _, err := gofix.RunUnusedPass([]string{file}, gofix.RunOptions{Mode: gofix.UnusedRemove, CachePath: cachePath}); err != nil {
+		t.Fatalf("RunUnusedPass: %v", err)
+	}
+
+	after, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read patched %s: %v", file, err)
+	}
+
+	cache, err := patch.LoadPatchCache(cachePath)
+	if err != nil {
+		t.Fatalf("LoadPatchCache: %v", err)
+	}
+	entry, ok := cache.Lookup(file, before)
+	if !ok {
+		t.Fatalf("expected a cache entry for %s keyed by its original content", file)
+	}
+	if string(entry.Patched) != string(after) {
+		t.Errorf("cached Patched = %q, want the content actually written: %q", entry.Patched, after)
+	}
+}
+
+// TestRunUnusedPassWritesProvenance exercises
+// scenarios/E001_go_unused_import_multi with RunOptions.ProvenanceSuffix
+// set: after the pass, a provenance.json sidecar must exist next to the
+// fixed file and name the rule (fix category) that produced the change.
+func TestRunUnusedPassWritesProvenance(t *testing.T) {
+	scenarioDir := filepath.Join("..", "..", "scenarios", "E001_go_unused_import_multi")
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join(scenarioDir, "repo"), workDir); err != nil {
+		t.Fatalf("copy scenario repo: %v", err)
+	}
+
+	file := filepath.Join(workDir, "strutil_test.go")
+	if _, err := gofix.RunUnusedPass([]string{file}, gofix.RunOptions{Mode: gofix.UnusedRemove, ProvenanceSuffix: ".provenance.json"}); err != nil {
+		t.Fatalf("RunUnusedPass: %v", err)
+	}
+
+	entries, err := patch.ReadProvenance(file + ".provenance.json")
+	if err != nil {
+		t.Fatalf("ReadProvenance: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("expected at least one provenance entry")
+	}
+	for _, e := range entries {
+		if e.Rule != string(gofix.CategoryUnusedImport) {
+			t.Errorf("entry Rule = %q, want %q", e.Rule, gofix.CategoryUnusedImport)
+		}
+		if e.AppliedAt.IsZero() {
+			t.Error("entry AppliedAt is zero")
+		}
+	}
+}
+
+// TestRunUnusedPassVerifyRollsBackIncompleteFix exercises
+// scenarios/E011_go_verify_incomplete_fix: RunUnusedPass's unused-import
+// fix is real, but the package still fails to build afterward because of
+// an unrelated undefined reference gofix has no fixer for. With Verify
+// set, that must be caught and the unused-import removal rolled back,
+// leaving the package exactly as broken - and exactly as it started - as
+// it was before the pass ran.
+func TestRunUnusedPassVerifyRollsBackIncompleteFix(t *testing.T) {
+	scenarioDir := filepath.Join("..", "..", "scenarios", "E011_go_verify_incomplete_fix")
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join(scenarioDir, "repo"), workDir); err != nil {
+		t.Fatalf("copy scenario repo: %v", err)
+	}
+
+	before := snapshot(t, workDir)
+
+	_, err := gofix.RunUnusedPass([]string{filepath.Join(workDir, "describe.go")}, gofix.RunOptions{Mode: gofix.UnusedRemove, Verify: true})
+	if err == nil {
+		t.Fatal("expected RunUnusedPass to fail verification")
+	}
+	var verifyErr *patch.VerifyError
+	if !errors.As(err, &verifyErr) {
+		t.Fatalf("expected a *patch.VerifyError, got %T: %v", err, err)
+	}
+
+	after := snapshot(t, workDir)
+	for path, want := range before {
+		if got := after[path]; got != want {
+			t.Errorf("%s was left patched despite verification failing:\nbefore: %q\nafter:  %q", path, want, got)
+		}
+	}
+}
+
+func snapshot(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[path] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("snapshot %s: %v", dir, err)
+	}
+	return files
+}
+
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// BenchmarkPatchWithoutCache and BenchmarkPatchWithCache both run
+// RunUnusedPass over every scenario repo once per b.N iteration, the only
+// difference being CachePath. Each iteration copies every scenario's repo/
+// fresh, so WithCache hits nothing on its own first iteration but every
+// iteration after that skips ProposeUnusedFix's AST parse/format for every
+// already-seen (file, content) pair. go vet itself - not AST work - is
+// most of either benchmark's wall time, so the delta between the two is a
+// lower bound on what PatchCache saves, not the whole picture.
+func BenchmarkPatchWithoutCache(b *testing.B) {
+	benchmarkUnusedPass(b, "")
+}
+
+func BenchmarkPatchWithCache(b *testing.B) {
+	benchmarkUnusedPass(b, filepath.Join(b.TempDir(), "cache.json"))
+}
+
+func benchmarkUnusedPass(b *testing.B, cachePath string) {
+	scenarioDirs, err := filepath.Glob(filepath.Join("..", "..", "scenarios", "E*"))
+	if err != nil {
+		b.Fatalf("glob scenarios: %v", err)
+	}
+	if len(scenarioDirs) == 0 {
+		b.Fatal("no scenarios found")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, scenarioDir := range scenarioDirs {
+			repoDir := filepath.Join(scenarioDir, "repo")
+			if _, err := os.Stat(filepath.Join(repoDir, "go.mod")); err != nil {
+				continue
+			}
+
+			workDir := b.TempDir()
+			if err := copyDir(repoDir, workDir); err != nil {
+				b.Fatalf("copy %s: %v", repoDir, err)
+			}
+			paths, err := filepath.Glob(filepath.Join(workDir, "*.go"))
+			if err != nil {
+				b.Fatalf("glob %s: %v", workDir, err)
+			}
+			if len(paths) == 0 {
+				continue
+			}
+			if _, err := gofix.RunUnusedPass(paths, gofix.RunOptions{Mode: gofix.UnusedRemove, CachePath: cachePath}); err != nil {
+				b.Fatalf("RunUnusedPass: %v", err)
+			}
+		}
+	}
+}
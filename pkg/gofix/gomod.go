@@ -0,0 +1,96 @@
+package gofix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// GoModPatcher adds missing `require` directives to a go.mod file, parsing
+// and re-serializing it with golang.org/x/mod/modfile so the rest of the
+// file - replace directives, comments, the go/toolchain lines - survives
+// untouched.
+type GoModPatcher struct {
+	// ProxyURL overrides GOPROXY when resolving a module's latest version.
+	// Empty means the environment's own GOPROXY setting.
+	ProxyURL string
+}
+
+// goListModuleVersion mirrors the subset of `go list -m -json <path>@latest`
+// output this package cares about.
+type goListModuleVersion struct {
+	Version string
+}
+
+// EnsureRequire reads the go.mod at goModPath and, if importPath isn't
+// already covered by a require directive, appends one pinned to the latest
+// version the module proxy reports, then writes the file back. It reports
+// whether it changed the file.
+//
+// golang.org/x/mod/module has no API to resolve importPath down to its
+// owning module root without walking its path prefixes against the proxy,
+// so this treats importPath itself as the module path - the overwhelmingly
+// common case for the single-package modules a missing-import diagnostic
+// usually names (e.g. github.com/pkg/errors).
+func (p GoModPatcher) EnsureRequire(goModPath, importPath string) (bool, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return false, fmt.Errorf("gofix: read %s: %w", goModPath, err)
+	}
+
+	f, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return false, fmt.Errorf("gofix: parse %s: %w", goModPath, err)
+	}
+
+	for _, req := range f.Require {
+		if req.Mod.Path == importPath {
+			return false, nil
+		}
+	}
+
+	version, err := p.latestVersion(filepath.Dir(goModPath), importPath)
+	if err != nil {
+		return false, fmt.Errorf("gofix: resolve latest version of %s: %w", importPath, err)
+	}
+
+	if err := f.AddRequire(importPath, version); err != nil {
+		return false, fmt.Errorf("gofix: add require %s: %w", importPath, err)
+	}
+	f.Cleanup()
+
+	out, err := f.Format()
+	if err != nil {
+		return false, fmt.Errorf("gofix: format %s: %w", goModPath, err)
+	}
+	if err := os.WriteFile(goModPath, out, 0o644); err != nil {
+		return false, fmt.Errorf("gofix: write %s: %w", goModPath, err)
+	}
+	return true, nil
+}
+
+// latestVersion shells out to `go list -m -json <modulePath>@latest`, run
+// from dir so it resolves against that module's own go.mod (and honors any
+// replace directive there).
+func (p GoModPatcher) latestVersion(dir, modulePath string) (string, error) {
+	cmd := exec.Command("go", "list", "-m", "-json", modulePath+"@latest")
+	cmd.Dir = dir
+	if p.ProxyURL != "" {
+		cmd.Env = append(os.Environ(), "GOPROXY="+p.ProxyURL)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	var m goListModuleVersion
+	if err := json.NewDecoder(bytes.NewReader(out)).Decode(&m); err != nil {
+		return "", fmt.Errorf("decode go list output: %w", err)
+	}
+	return m.Version, nil
+}
@@ -0,0 +1,127 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ImportFixOptions configures ProposeMissingImportFix.
+type ImportFixOptions struct {
+	// ModDir is the directory containing the go.mod that owns the file
+	// being fixed. Required for FixGoMod.
+	ModDir string
+	// FixGoMod, when true, adds a `require` directive for a third-party
+	// import path that ProposeMissingImportFix resolves but ModDir's
+	// go.mod doesn't yet list - otherwise the .go fix would compile the
+	// import correctly but `go build` would still fail on the missing
+	// dependency.
+	FixGoMod bool
+	// GoModProxy overrides the module proxy GoModPatcher queries for the
+	// dependency's latest version. Empty means the environment's GOPROXY.
+	GoModProxy string
+}
+
+// missingImportFixer is the built-in Fixer behind ProposeMissingImportFix,
+// registered per-call (rather than into DefaultRegistry at init time)
+// because it needs idx and opts, both scoped to the package being fixed.
+type missingImportFixer struct {
+	idx  *SymbolIndex
+	opts ImportFixOptions
+}
+
+func (f missingImportFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryMissingImport
+}
+
+// Apply resolves the diagnostic's bare package name against f.idx and adds
+// the winning candidate as an import. It reports no change, rather than an
+// error, when the symbol can't be resolved or the package is already
+// imported - in either case the real problem is a bad reference (wrong
+// case, typo), not a missing import, and a Fixer shouldn't propose a no-op
+// patch that would tie with the real fix for that case.
+//
+// When f.opts.FixGoMod is set and the resolved import path is third-party,
+// this also writes a `require` directive to ModDir's go.mod as a side
+// effect, ahead of the AST edit - the two have to land together for the
+// import to actually build.
+func (f missingImportFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fix := Classify(diag)
+	ranked := Ranker{Index: f.idx, File: file}.RankedCandidates(fix.Package, 1)
+	if len(ranked) == 0 {
+		return false, nil
+	}
+	importPath := ranked[0].Path
+
+	if alreadyImports(file, importPath) {
+		return false, nil
+	}
+
+	if f.opts.FixGoMod && f.opts.ModDir != "" && !isStdlibPath(importPath) {
+		patcher := GoModPatcher{ProxyURL: f.opts.GoModProxy}
+		if _, err := patcher.EnsureRequire(filepath.Join(f.opts.ModDir, "go.mod"), importPath); err != nil {
+			return false, fmt.Errorf("gofix: %w", err)
+		}
+	}
+
+	astutil.AddImport(fset, file, importPath)
+	return true, nil
+}
+
+// ProposeMissingImportFix resolves a CategoryMissingImport Fix through the
+// built-in missingImportFixer and returns the file's source with the
+// needed import inserted, plus the patch.Result describing the edit. It
+// returns a nil results slice if fix isn't a missing-import fix or the
+// Fixer reports no change.
+func ProposeMissingImportFix(src []byte, fix Fix, idx *SymbolIndex, opts ImportFixOptions) ([]byte, []patch.Result, error) {
+	if fix.Category != CategoryMissingImport {
+		return nil, nil, nil
+	}
+
+	fset := token.NewFileSet()
+	fileAST, err := parser.ParseFile(fset, fix.Diagnostic.File, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	reg := NewRegistry()
+	reg.Register("missing-import", missingImportFixer{idx: idx, opts: opts})
+
+	var changed bool
+	for _, fixer := range reg.For(fix.Diagnostic) {
+		c, err := fixer.Apply(fset, fileAST, fix.Diagnostic)
+		if err != nil {
+			return nil, nil, err
+		}
+		changed = changed || c
+	}
+	if !changed {
+		return nil, nil, nil
+	}
+
+	if opts.ModDir != "" {
+		if err := FormatImports(fset, fileAST, modulePathOf(opts.ModDir)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, fileAST); err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+	out := buf.Bytes()
+
+	bs, be, as, ae := patch.DiffLines(src, out)
+	result := patch.Result{
+		File: fix.Diagnostic.File, Kind: patch.KindAddImport, Changed: true,
+		BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+	}
+	return out, []patch.Result{result}, nil
+}
@@ -0,0 +1,184 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// importBlockRE matches a gofmt-rendered, parenthesized import block - the
+// form go/printer always uses once a declaration has two or more specs,
+// which FormatImports only ever operates on.
+var importBlockRE = regexp.MustCompile(`(?s)import \(\n.*?\n\)\n`)
+
+// FormatImports re-groups and sorts file's import block into the
+// goimports convention - standard library first, then third-party, then
+// modulePath's own packages - with a single blank line between
+// non-empty groups, so a block a Fixer grew by calling astutil.AddImport
+// several times over (which only ever inserts into whichever group it
+// guesses is closest) ends up in the order every other file in the
+// project already follows.
+//
+// Every other AST mutator in this package takes a *token.FileSet because
+// it edits Pos-bearing nodes in place; FormatImports needs one for a
+// different reason, and doesn't actually edit file's nodes in place at
+// all: the only way to give go/printer real blank-line gaps between the
+// new groups is to render the desired block as text. Splicing a
+// freshly-parsed replacement GenDecl's Pos-bearing fields into the
+// existing file doesn't work - the new nodes are registered in fset after
+// file's own, so their positions are always numerically larger than
+// file's, and go/printer's comment placement (which assumes strictly
+// increasing positions through the whole file) then treats any comment
+// between the import block and the following declaration - typically a
+// doc comment, i.e. nearly every file - as if it were still inside the
+// import parens. FormatImports instead renders the *entire* file to text
+// with file's current (ungrouped) import block, replaces just that block
+// textually, and reparses the result into fset, replacing file's contents
+// wholesale. Specs are reduced to their alias and path in the process - a
+// per-import trailing comment is not preserved, since there is no
+// Pos-safe way to carry it across the reparse without also re-deriving
+// its line gap.
+//
+// FormatImports is idempotent: its own output, grouped and sorted, is a
+// fixed point of another call.
+func FormatImports(fset *token.FileSet, file *ast.File, modulePath string) error {
+	decl := importGenDecl(file)
+	if decl == nil || len(decl.Specs) < 2 {
+		return nil
+	}
+
+	groups := groupImportSpecs(decl.Specs, modulePath)
+	block := renderImportBlock(groups)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return fmt.Errorf("gofix: render for import formatting: %w", err)
+	}
+	src := buf.String()
+
+	loc := importBlockRE.FindStringIndex(src)
+	if loc == nil {
+		return fmt.Errorf("gofix: could not locate a parenthesized import block to reformat")
+	}
+	newSrc := src[:loc[0]] + block + src[loc[1]:]
+
+	newFile, err := parser.ParseFile(fset, "", newSrc, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("gofix: reparse after import formatting: %w", err)
+	}
+	*file = *newFile
+	return nil
+}
+
+// modulePathOf returns the module path declared by the go.mod owning dir,
+// or "" if none is found or it fails to parse - FormatImports's internal
+// callers treat that as "no internal group", the same way a file outside
+// any module would have no third group to sort into.
+func modulePathOf(dir string) string {
+	goModPath := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return ""
+	}
+	f, err := modfile.Parse(goModPath, data, nil)
+	if err != nil || f.Module == nil {
+		return ""
+	}
+	return f.Module.Mod.Path
+}
+
+// importGenDecl returns file's import declaration (there is at most one;
+// gofmt already merges multiple `import` blocks into one), or nil if file
+// has no imports.
+func importGenDecl(file *ast.File) *ast.GenDecl {
+	for _, d := range file.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			return gd
+		}
+	}
+	return nil
+}
+
+// importSpecText is an import spec reduced to the two fields
+// FormatImports preserves across the reparse.
+type importSpecText struct {
+	Name string // "" if unaliased, "_" for a blank import, "." for dot-import
+	Path string // unquoted
+}
+
+// groupImportSpecs buckets specs into goimports' three standard groups -
+// standard library, third-party, and modulePath's own packages - each
+// sorted by path, with dot- and blank-imports sorting alongside named
+// ones in the same group (gofmt's own `goimports` does the same; it does
+// not give them a fourth group).
+func groupImportSpecs(specs []ast.Spec, modulePath string) [][]importSpecText {
+	var stdlib, external, internal []importSpecText
+	for _, s := range specs {
+		imp := s.(*ast.ImportSpec)
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			path = imp.Path.Value
+		}
+		var name string
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		entry := importSpecText{Name: name, Path: path}
+
+		switch {
+		case modulePath != "" && (path == modulePath || strings.HasPrefix(path, modulePath+"/")):
+			internal = append(internal, entry)
+		case isStdlibPath(path):
+			stdlib = append(stdlib, entry)
+		default:
+			external = append(external, entry)
+		}
+	}
+
+	for _, group := range [][]importSpecText{stdlib, external, internal} {
+		sort.Slice(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+	}
+
+	var groups [][]importSpecText
+	for _, group := range [][]importSpecText{stdlib, external, internal} {
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// renderImportBlock renders groups as a parenthesized import declaration,
+// with a blank line between groups, suitable for parsing back via
+// parser.ParseFile.
+func renderImportBlock(groups [][]importSpecText) string {
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for i, group := range groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, entry := range group {
+			b.WriteString("\t")
+			if entry.Name != "" {
+				b.WriteString(entry.Name)
+				b.WriteString(" ")
+			}
+			b.WriteString(strconv.Quote(entry.Path))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package gofix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFormatImportsGroupsStdlibExternalAndInternal(t *testing.T) {
+	const src = `package p
+
+import (
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"os"
+)
+
+func run() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	if err := FormatImports(fset, file, "github.com/FernandoTN/repo-patcher"); err != nil {
+		t.Fatalf("FormatImports: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	wantOrder := []string{`"fmt"`, `"os"`, `"github.com/fsnotify/fsnotify"`, `"github.com/FernandoTN/repo-patcher/pkg/patch"`}
+	lastIdx := -1
+	for _, want := range wantOrder {
+		idx := strings.Index(out, want)
+		if idx < 0 {
+			t.Fatalf("got:\n%s\nwant import %s", out, want)
+		}
+		if idx < lastIdx {
+			t.Errorf("got:\n%s\nimport %s out of order (want stdlib, external, internal)", out, want)
+		}
+		lastIdx = idx
+	}
+
+	if got := strings.Count(out, "\n\n"); got < 2 {
+		t.Errorf("got:\n%s\nwant at least 2 blank-line separated groups (3 groups), got %d blank lines", out, got)
+	}
+}
+
+func TestFormatImportsIsIdempotent(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	stdlibPaths := []string{"fmt", "os", "strings", "io", "net/http", "context", "sort"}
+	externalPaths := []string{"github.com/fsnotify/fsnotify", "golang.org/x/mod/modfile", "gopkg.in/yaml.v3", "github.com/prometheus/client_golang/prometheus"}
+	modulePath := "github.com/FernandoTN/repo-patcher"
+	internalPaths := []string{modulePath + "/pkg/patch", modulePath + "/pkg/gofix", modulePath + "/pkg/sarif"}
+
+	for i := 0; i < 50; i++ {
+		var paths []string
+		for _, pool := range [][]string{stdlibPaths, externalPaths, internalPaths} {
+			n := rng.Intn(len(pool) + 1)
+			perm := rng.Perm(len(pool))[:n]
+			for _, idx := range perm {
+				paths = append(paths, pool[idx])
+			}
+		}
+		rng.Shuffle(len(paths), func(a, b int) { paths[a], paths[b] = paths[b], paths[a] })
+		if len(paths) < 2 {
+			continue
+		}
+
+		var importLines strings.Builder
+		for _, p := range paths {
+			fmt.Fprintf(&importLines, "\t%q\n", p)
+		}
+		src := "package p\n\nimport (\n" + importLines.String() + ")\n"
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "p.go", src, 0)
+		if err != nil {
+			t.Fatalf("case %d: ParseFile: %v\nsrc:\n%s", i, err, src)
+		}
+
+		if err := FormatImports(fset, file, modulePath); err != nil {
+			t.Fatalf("case %d: first FormatImports: %v", i, err)
+		}
+		first := renderFile(t, fset, file)
+
+		if err := FormatImports(fset, file, modulePath); err != nil {
+			t.Fatalf("case %d: second FormatImports: %v", i, err)
+		}
+		second := renderFile(t, fset, file)
+
+		if first != second {
+			t.Fatalf("case %d: FormatImports is not idempotent:\nfirst:\n%s\nsecond:\n%s", i, first, second)
+		}
+	}
+}
+
+func renderFile(t *testing.T, fset *token.FileSet, file *ast.File) string {
+	t.Helper()
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,156 @@
+package gofix
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// InitOrderIssue reports one top-level var block whose declaration order
+// disagrees with the order go/types computed for its actual
+// initialization - legal Go (the compiler always initializes by
+// dependency order, never declaration order) but a common source of
+// confusion for a reader who assumes the two match.
+type InitOrderIssue struct {
+	// Decl is the var block (an *ast.GenDecl with Tok == token.VAR) whose
+	// Specs are out of order.
+	Decl *ast.GenDecl
+	// Declared lists the block's variable names in source order.
+	Declared []string
+	// WantOrder lists the same names in the order they're actually
+	// initialized.
+	WantOrder []string
+}
+
+// DetectInitOrderIssues compares each top-level var block's declaration
+// order in file against info.InitOrder (populated by a prior types.Check
+// of file) and reports every block where they disagree. A block is only
+// considered when every spec in it declares exactly one name with exactly
+// one initializer expression - the only shape ReorderInitDecls can safely
+// reorder without also having to reason about a single multi-value RHS
+// shared across names, or the side effect order of a spec with no
+// initializer.
+func DetectInitOrderIssues(file *ast.File, info *types.Info) []InitOrderIssue {
+	rank := initOrderRank(info)
+
+	var issues []InitOrderIssue
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR || !reorderableVarBlock(gd) {
+			continue
+		}
+
+		declared := make([]string, len(gd.Specs))
+		ranks := make([]int, len(gd.Specs))
+		for i, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			name := vs.Names[0].Name
+			declared[i] = name
+			r, ok := rank[name]
+			if !ok {
+				// A blank identifier or a name types.Check didn't resolve
+				// to a package-level object; leave this block alone
+				// rather than guess at its place in the order.
+				ranks = nil
+				break
+			}
+			ranks[i] = r
+		}
+		if ranks == nil || sortedInts(ranks) {
+			continue
+		}
+
+		wantOrder := append([]string(nil), declared...)
+		sortByRank(wantOrder, ranks)
+		issues = append(issues, InitOrderIssue{Decl: gd, Declared: declared, WantOrder: wantOrder})
+	}
+	return issues
+}
+
+// InitOrderFixer reorders a file's package-level var declarations to match
+// their true go/types initialization order. Unlike the repo's other
+// Fixers, it isn't driven by a compiler diagnostic - go build and go vet
+// have nothing to say about this, since the declaration order never
+// affects program behavior - so it doesn't implement the Fixer interface
+// or register with DefaultRegistry; a caller runs it directly wherever it
+// already has a type-checked *types.Info for the file (e.g. as an
+// additional pass after the diagnostic-driven fixers have run).
+type InitOrderFixer struct{}
+
+// Fix reorders file's var blocks in place and reports whether it changed
+// anything. See ReorderInitDecls.
+func (InitOrderFixer) Fix(file *ast.File, info *types.Info) bool {
+	return ReorderInitDecls(file, info)
+}
+
+// ReorderInitDecls rewrites every top-level var block DetectInitOrderIssues
+// flags so its Specs appear in true initialization order, and reports
+// whether it changed anything. It only ever reorders a block's existing
+// Specs in place - never renaming, retyping, adding, or removing a
+// variable - so it cannot change the package's exported API surface.
+func ReorderInitDecls(file *ast.File, info *types.Info) bool {
+	issues := DetectInitOrderIssues(file, info)
+	for _, issue := range issues {
+		byName := map[string]ast.Spec{}
+		for _, spec := range issue.Decl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			byName[vs.Names[0].Name] = spec
+		}
+		reordered := make([]ast.Spec, len(issue.WantOrder))
+		for i, name := range issue.WantOrder {
+			reordered[i] = byName[name]
+		}
+		issue.Decl.Specs = reordered
+	}
+	return len(issues) > 0
+}
+
+// initOrderRank maps each package-level initializer's variable name to its
+// position in info.InitOrder. A multi-name initializer ("var a, b = f()")
+// gives every name on its left side the same rank, since go/types doesn't
+// order them relative to each other - they're initialized together.
+func initOrderRank(info *types.Info) map[string]int {
+	rank := map[string]int{}
+	for i, init := range info.InitOrder {
+		for _, v := range init.Lhs {
+			rank[v.Name()] = i
+		}
+	}
+	return rank
+}
+
+// reorderableVarBlock reports whether every spec in gd declares exactly
+// one name with exactly one initializer expression.
+func reorderableVarBlock(gd *ast.GenDecl) bool {
+	if len(gd.Specs) < 2 {
+		return false
+	}
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Names) != 1 || len(vs.Values) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedInts(ranks []int) bool {
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i] < ranks[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortByRank sorts names in place by rank[name] via ranks, which is
+// parallel to names at call time; simple insertion sort since var blocks
+// are never large enough to need better.
+func sortByRank(names []string, ranks []int) {
+	for i := 1; i < len(names); i++ {
+		for j := i; j > 0 && ranks[j] < ranks[j-1]; j-- {
+			names[j], names[j-1] = names[j-1], names[j]
+			ranks[j], ranks[j-1] = ranks[j-1], ranks[j]
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package gofix
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// typeCheck parses src as file.go and runs the type checker over it,
+// returning the parsed file and the Info populated with InitOrder - the
+// same pair DetectInitOrderIssues/ReorderInitDecls expect a caller to have
+// already produced.
+func typeCheck(t *testing.T, src string) (*ast.File, *types.Info) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "file.go", src, 0)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	info := &types.Info{InitOrder: nil}
+	cfg := types.Config{Importer: importer.Default()}
+	if _, err := cfg.Check("p", fset, []*ast.File{f}, info); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	return f, info
+}
+
+func formatFile(t *testing.T, f *ast.File) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), f); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	return buf.String()
+}
+
+func TestDetectInitOrderIssuesFindsThreeVariableCycle(t *testing.T) {
+	const src = `package p
+
+var (
+	a = b + 1
+	b = c + 1
+	c = 2
+)
+`
+	f, info := typeCheck(t, src)
+
+	issues := DetectInitOrderIssues(f, info)
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+	issue := issues[0]
+	if got, want := issue.Declared, []string{"a", "b", "c"}; !equalStrings(got, want) {
+		t.Errorf("Declared = %v, want %v", got, want)
+	}
+	if got, want := issue.WantOrder, []string{"c", "b", "a"}; !equalStrings(got, want) {
+		t.Errorf("WantOrder = %v, want %v", got, want)
+	}
+}
+
+func TestDetectInitOrderIssuesIgnoresAlreadyOrderedBlock(t *testing.T) {
+	const src = `package p
+
+var (
+	c = 2
+	b = c + 1
+	a = b + 1
+)
+`
+	f, info := typeCheck(t, src)
+
+	if issues := DetectInitOrderIssues(f, info); len(issues) != 0 {
+		t.Fatalf("got %d issues, want 0: %+v", len(issues), issues)
+	}
+}
+
+func TestReorderInitDeclsProducesEquivalentCompilableOutput(t *testing.T) {
+	const src = `package p
+
+var (
+	a = b + 1
+	b = c + 1
+	c = 2
+)
+`
+	f, info := typeCheck(t, src)
+	wantOrder := initOrderNames(info)
+
+	if changed := ReorderInitDecls(f, info); !changed {
+		t.Fatal("ReorderInitDecls reported no change, want a reorder")
+	}
+
+	out := formatFile(t, f)
+	f2, info2 := typeCheck(t, out)
+	if issues := DetectInitOrderIssues(f2, info2); len(issues) != 0 {
+		t.Errorf("reordered output still has issues: %+v", issues)
+	}
+	if got := initOrderNames(info2); !equalStrings(got, wantOrder) {
+		t.Errorf("reordering changed the initialization sequence: got %v, want %v", got, wantOrder)
+	}
+}
+
+func TestReorderInitDeclsLeavesMultiNameSpecsAlone(t *testing.T) {
+	const src = `package p
+
+var a, b = 1, 2
+var c = a + b
+`
+	f, info := typeCheck(t, src)
+
+	if changed := ReorderInitDecls(f, info); changed {
+		t.Error("ReorderInitDecls reported a change for a block with no reorderable issue")
+	}
+}
+
+func initOrderNames(info *types.Info) []string {
+	var names []string
+	for _, init := range info.InitOrder {
+		for _, v := range init.Lhs {
+			names = append(names, v.Name())
+		}
+	}
+	return names
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,283 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// InterfaceComplianceFixer is the built-in Fixer for CategoryMissingMethod:
+// the compiler's "T does not implement I (missing method M)" error. It
+// finds I's declaration in the same file, copies M's signature from there,
+// and appends a stub implementation to T - matching T's existing receiver
+// style (pointer or value) when T already has other methods, and defaulting
+// to whichever form the diagnostic's offending value actually was
+// otherwise. The stub's body is a single "// TODO: implement" comment
+// followed by a return of each result type's zero value, so the file
+// compiles immediately; go vet only ever reports one missing method per
+// diagnostic, so a type missing several methods needs this Fixer run once
+// per method, the same way CategoryErrorReturnMismatch needs repeated
+// passes to reach every call site in a file.
+type InterfaceComplianceFixer struct{}
+
+func init() {
+	DefaultRegistry.Register("missing-method", InterfaceComplianceFixer{})
+}
+
+func (InterfaceComplianceFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryMissingMethod
+}
+
+// Apply appends fix.Symbol's stub to fix.SourceType's declaration file.
+func (InterfaceComplianceFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fix := Classify(diag)
+	return addMissingMethodStub(fset, file, fix)
+}
+
+func addMissingMethodStub(fset *token.FileSet, file *ast.File, fix Fix) (bool, error) {
+	typeName := strings.TrimPrefix(fix.SourceType, "*")
+
+	method := findInterfaceMethod(file, fix.TargetType, fix.Symbol)
+	if method == nil {
+		return false, fmt.Errorf("gofix: interface %s has no method %s in this file", fix.TargetType, fix.Symbol)
+	}
+
+	pointerReceiver := strings.HasPrefix(fix.SourceType, "*")
+	if style, ok := receiverStyle(file, typeName); ok {
+		pointerReceiver = style
+	}
+
+	decl, err := buildStubDecl(fset, typeName, pointerReceiver, fix.Symbol, method)
+	if err != nil {
+		return false, err
+	}
+
+	file.Decls = append(file.Decls, decl)
+	return true, nil
+}
+
+// findInterfaceMethod returns the *ast.FuncType of ifaceName's methodName
+// method as declared in file, or nil if either isn't found there.
+func findInterfaceMethod(file *ast.File, ifaceName, methodName string) *ast.FuncType {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != ifaceName {
+				continue
+			}
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			for _, m := range it.Methods.List {
+				ft, ok := m.Type.(*ast.FuncType)
+				if !ok {
+					continue
+				}
+				for _, name := range m.Names {
+					if name.Name == methodName {
+						return ft
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// receiverStyle reports whether typeName's existing methods in file use a
+// pointer receiver, and whether it found any methods to judge by at all.
+func receiverStyle(file *ast.File, typeName string) (pointer bool, found bool) {
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 {
+			continue
+		}
+		switch t := fd.Recv.List[0].Type.(type) {
+		case *ast.StarExpr:
+			if id, ok := t.X.(*ast.Ident); ok && id.Name == typeName {
+				return true, true
+			}
+		case *ast.Ident:
+			if t.Name == typeName {
+				return false, true
+			}
+		}
+	}
+	return false, false
+}
+
+// buildStubDecl builds a "func (recv [*]typeName) methodName(...) (...) {
+// // TODO: implement \n return zero, values... }" declaration matching
+// method's signature, cloned out of the interface declaration it came
+// from so the stub doesn't share AST nodes (and their source positions)
+// with it.
+func buildStubDecl(fset *token.FileSet, typeName string, pointerReceiver bool, methodName string, method *ast.FuncType) (*ast.FuncDecl, error) {
+	params, err := cloneFieldListPreserveNames(fset, method.Params)
+	if err != nil {
+		return nil, err
+	}
+	sigResults, err := cloneFieldListPreserveNames(fset, method.Results)
+	if err != nil {
+		return nil, err
+	}
+	// bodyResults is a second, independently-named clone of the same
+	// results: it backs the stub's "var ret0 T0; return ret0" body, kept
+	// separate from sigResults so forcing names there (needed to have
+	// something to assign and return) never turns an originally unnamed
+	// result into a named one in the stub's own signature.
+	bodyResults, err := cloneFieldList(fset, method.Results, "ret")
+	if err != nil {
+		return nil, err
+	}
+
+	var recvType ast.Expr = ast.NewIdent(typeName)
+	if pointerReceiver {
+		recvType = &ast.StarExpr{X: ast.NewIdent(typeName)}
+	}
+	recvName := strings.ToLower(typeName[:1])
+
+	var stmts []ast.Stmt
+	if bodyResults != nil {
+		retNames := make([]ast.Expr, 0, len(bodyResults.List))
+		for _, f := range bodyResults.List {
+			for _, name := range f.Names {
+				stmts = append(stmts, &ast.DeclStmt{Decl: &ast.GenDecl{
+					Tok:   token.VAR,
+					Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{ast.NewIdent(name.Name)}, Type: f.Type}},
+				}})
+				retNames = append(retNames, ast.NewIdent(name.Name))
+			}
+		}
+		stmts = append(stmts, &ast.ReturnStmt{Results: retNames})
+	}
+
+	decl := &ast.FuncDecl{
+		Doc: &ast.CommentGroup{List: []*ast.Comment{{Text: "// TODO: implement"}}},
+		Recv: &ast.FieldList{List: []*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent(recvName)}, Type: recvType},
+		}},
+		Name: ast.NewIdent(methodName),
+		Type: &ast.FuncType{Params: params, Results: sigResults},
+		Body: &ast.BlockStmt{List: stmts},
+	}
+	return decl, nil
+}
+
+// cloneFieldListPreserveNames deep-copies fl the same way cloneFieldList
+// does, but keeps each field's original naming exactly (including leaving
+// an unnamed field unnamed) rather than forcing one - used for a stub's
+// own signature, where an interface method that didn't name its
+// parameters or results shouldn't suddenly grow names gofix invented.
+func cloneFieldListPreserveNames(fset *token.FileSet, fl *ast.FieldList) (*ast.FieldList, error) {
+	if fl == nil {
+		return nil, nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range fl.List {
+		t, err := cloneExpr(fset, f.Type)
+		if err != nil {
+			return nil, err
+		}
+		field := &ast.Field{Type: t}
+		for _, name := range f.Names {
+			field.Names = append(field.Names, ast.NewIdent(name.Name))
+		}
+		out.List = append(out.List, field)
+	}
+	return out, nil
+}
+
+// cloneFieldList deep-copies fl (an interface method's params or results)
+// by round-tripping each field's type through go/printer and go/parser,
+// so the clone shares no AST nodes or source positions with the original
+// interface declaration. Every field is given a name (reusing the
+// original if the interface signature named it, else prefixPlus an
+// index) since a stub's body needs something to assign and return.
+func cloneFieldList(fset *token.FileSet, fl *ast.FieldList, prefix string) (*ast.FieldList, error) {
+	if fl == nil {
+		return nil, nil
+	}
+	out := &ast.FieldList{}
+	n := 0
+	for _, f := range fl.List {
+		names := f.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{}}
+		}
+		for _, orig := range names {
+			name := orig.Name
+			if name == "" || name == "_" {
+				name = fmt.Sprintf("%s%d", prefix, n)
+			}
+			n++
+			t, err := cloneExpr(fset, f.Type)
+			if err != nil {
+				return nil, err
+			}
+			out.List = append(out.List, &ast.Field{Names: []*ast.Ident{ast.NewIdent(name)}, Type: t})
+		}
+	}
+	return out, nil
+}
+
+// cloneExpr renders e as source text and re-parses it, producing a fresh
+// expression tree with its own source positions.
+func cloneExpr(fset *token.FileSet, e ast.Expr) (ast.Expr, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, e); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	expr, err := parser.ParseExpr(buf.String())
+	if err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return expr, nil
+}
+
+// ProposeMissingMethodFix resolves a CategoryMissingMethod Fix through
+// InterfaceComplianceFixer and returns the file's source with the stub
+// method appended, plus the patch.Result describing the edit.
+func ProposeMissingMethodFix(src []byte, fix Fix) ([]byte, []patch.Result, error) {
+	if fix.Category != CategoryMissingMethod {
+		return nil, nil, nil
+	}
+
+	fset := token.NewFileSet()
+	fileAST, err := parser.ParseFile(fset, fix.Diagnostic.File, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	changed, err := addMissingMethodStub(fset, fileAST, fix)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !changed {
+		return nil, nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, fileAST); err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+	out := buf.Bytes()
+
+	bs, be, as, ae := patch.DiffLines(src, out)
+	result := patch.Result{
+		File: fix.Diagnostic.File, Kind: patch.KindAddMethodStub, Changed: true,
+		BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+	}
+	return out, []patch.Result{result}, nil
+}
@@ -0,0 +1,113 @@
+package gofix_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+func TestClassifyMissingMethod(t *testing.T) {
+	d := gofix.Diagnostic{
+		File: "main.go", Line: 14, Col: 18,
+		Message: `cannot use Dog{} (value of type Dog) as Greeter value in variable declaration: Dog does not implement Greeter (missing method Name)`,
+	}
+	fix := gofix.Classify(d)
+	if fix.Category != gofix.CategoryMissingMethod {
+		t.Fatalf("got category %v, want %v", fix.Category, gofix.CategoryMissingMethod)
+	}
+	if fix.TargetType != "Greeter" || fix.SourceType != "Dog" || fix.Symbol != "Name" {
+		t.Errorf("got Fix=%+v, want TargetType=Greeter SourceType=Dog Symbol=Name", fix)
+	}
+}
+
+func TestProposeMissingMethodFixAppendsValueReceiverStub(t *testing.T) {
+	const src = `package main
+
+type Greeter interface {
+	Greet() string
+	Name() string
+}
+
+type Dog struct{}
+
+func (d Dog) Greet() string { return "woof" }
+
+func main() {
+	var g Greeter = Dog{}
+	_ = g
+}
+`
+	fix := gofix.Fix{
+		Category:   gofix.CategoryMissingMethod,
+		Diagnostic: gofix.Diagnostic{File: "main.go", Line: 13},
+		TargetType: "Greeter",
+		SourceType: "Dog",
+		Symbol:     "Name",
+	}
+
+	out, results, err := gofix.ProposeMissingMethodFix([]byte(src), fix)
+	if err != nil {
+		t.Fatalf("ProposeMissingMethodFix: %v", err)
+	}
+	if len(results) != 1 || !results[0].Changed {
+		t.Fatalf("got results=%+v, want one Changed result", results)
+	}
+
+	got := string(out)
+	if !strings.Contains(got, "// TODO: implement") {
+		t.Errorf("got:\n%s\nwant a TODO comment on the stub", got)
+	}
+	if !strings.Contains(got, "func (d Dog) Name() string {") {
+		t.Errorf("got:\n%s\nwant a value-receiver Name stub, matching Dog's existing Greet receiver", got)
+	}
+}
+
+func TestProposeMissingMethodFixAppendsPointerReceiverStubWithMultipleResults(t *testing.T) {
+	const src = `package main
+
+type Greeter interface {
+	Score(a int, b string) (int, error)
+}
+
+type Dog struct{}
+
+func useGreeter(g Greeter) {}
+
+func main() {
+	d := &Dog{}
+	useGreeter(d)
+}
+`
+	fix := gofix.Fix{
+		Category:   gofix.CategoryMissingMethod,
+		Diagnostic: gofix.Diagnostic{File: "main.go", Line: 12},
+		TargetType: "Greeter",
+		SourceType: "*Dog",
+		Symbol:     "Score",
+	}
+
+	out, results, err := gofix.ProposeMissingMethodFix([]byte(src), fix)
+	if err != nil {
+		t.Fatalf("ProposeMissingMethodFix: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got results=%+v, want one result", results)
+	}
+
+	got := string(out)
+	if !strings.Contains(got, "func (d *Dog) Score(a int, b string) (int, error) {") {
+		t.Errorf("got:\n%s\nwant a pointer-receiver Score stub with the interface's own parameter names", got)
+	}
+	if !strings.Contains(got, "var ") {
+		t.Errorf("got:\n%s\nwant zero-valued local results returned", got)
+	}
+}
+
+func TestProposeMissingMethodFixIgnoresOtherCategories(t *testing.T) {
+	fix := gofix.Fix{Category: gofix.CategoryUnusedImport, Diagnostic: gofix.Diagnostic{File: "main.go"}}
+	out, results, err := gofix.ProposeMissingMethodFix([]byte("package main\n"), fix)
+	if err != nil || out != nil || results != nil {
+		t.Errorf("got out=%q results=%v err=%v, want all nil for a non-missing-method Fix", out, results, err)
+	}
+}
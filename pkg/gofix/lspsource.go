@@ -0,0 +1,60 @@
+package gofix
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/lspclient"
+)
+
+// LSPDiagnosticSource collects diagnostics for Files from a running gopls
+// instance (see lspclient.Start) via textDocument/didOpen and
+// textDocument/publishDiagnostics, instead of invoking `go vet` itself -
+// the same diagnostics a gopls-backed editor would show, including
+// analyses go vet doesn't run by default.
+type LSPDiagnosticSource struct {
+	Client *lspclient.Client
+	Files  []string
+	// Timeout bounds how long to wait for each file's diagnostics to
+	// publish after DidOpen; it defaults to 5 seconds when zero.
+	Timeout time.Duration
+}
+
+// Diagnostics implements DiagnosticSource.
+func (s LSPDiagnosticSource) Diagnostics() ([]Diagnostic, error) {
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	for _, path := range s.Files {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("gofix: read %s: %w", path, err)
+		}
+		if err := s.Client.DidOpen(path, string(contents)); err != nil {
+			return nil, fmt.Errorf("gofix: didOpen %s: %w", path, err)
+		}
+	}
+
+	var diags []Diagnostic
+	for _, path := range s.Files {
+		for _, d := range s.Client.WaitForDiagnostics(path, timeout) {
+			diags = append(diags, lspDiagnosticToDiagnostic(path, d))
+		}
+	}
+	return diags, nil
+}
+
+// lspDiagnosticToDiagnostic converts gopls's 0-based LSP Range into this
+// package's 1-based file:line:col Diagnostic, the convention ParseDiagnostics
+// and GoVetSource's finding positions already use.
+func lspDiagnosticToDiagnostic(path string, d lspclient.Diagnostic) Diagnostic {
+	return Diagnostic{
+		File:    path,
+		Line:    d.Range.Start.Line + 1,
+		Col:     d.Range.Start.Character + 1,
+		Message: d.Message,
+	}
+}
@@ -0,0 +1,218 @@
+package gofix_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/lspclient"
+)
+
+// fakeGopls speaks just enough of gopls's JSON-RPC 2.0, Content-Length
+// framed stdio protocol to drive LSPDiagnosticSource's test without
+// spawning a real gopls: it answers "initialize"/"shutdown" with an empty
+// result and answers each "textDocument/didOpen" notification by
+// publishing the diagnostics canned for that file's URI.
+type fakeGopls struct {
+	in    *bufio.Reader
+	out   io.Writer
+	diags map[string][]lspclient.Diagnostic
+}
+
+func (g *fakeGopls) serve() {
+	for {
+		msg, err := readFrame(g.in)
+		if err != nil {
+			return
+		}
+		var env struct {
+			ID     *int64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(msg, &env); err != nil {
+			continue
+		}
+		switch env.Method {
+		case "initialize", "shutdown":
+			writeFrame(g.out, map[string]interface{}{
+				"jsonrpc": "2.0", "id": *env.ID, "result": map[string]interface{}{"capabilities": map[string]interface{}{}},
+			})
+		case "textDocument/didOpen":
+			var p struct {
+				TextDocument struct {
+					URI string `json:"uri"`
+				} `json:"textDocument"`
+			}
+			_ = json.Unmarshal(env.Params, &p)
+			ds, ok := g.diags[p.TextDocument.URI]
+			if !ok {
+				// Simulates a gopls that hasn't finished analyzing this
+				// file yet: no publishDiagnostics notification at all.
+				continue
+			}
+			writeFrame(g.out, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "textDocument/publishDiagnostics",
+				"params": map[string]interface{}{
+					"uri":         p.TextDocument.URI,
+					"diagnostics": ds,
+				},
+			})
+		case "exit":
+			return
+		}
+	}
+}
+
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	var length int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == "\r\n" || line == "\n" {
+			break
+		}
+		fmt.Sscanf(line, "Content-Length: %d", &length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func writeFrame(w io.Writer, v interface{}) {
+	body, _ := json.Marshal(v)
+	fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body))
+	w.Write(body)
+}
+
+// fileURI mirrors lspclient's own (unexported) path-to-URI conversion, so
+// diags keyed by URI here line up with what the Client under test sends on
+// DidOpen.
+func fileURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// newFakeClient wires a *lspclient.Client up against fakeGopls instead of a
+// real gopls subprocess, so this test never shells out to a tool that may
+// not be installed wherever it runs.
+func newFakeClient(t *testing.T, dir string, diags map[string][]lspclient.Diagnostic) *lspclient.Client {
+	t.Helper()
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+
+	g := &fakeGopls{in: bufio.NewReader(reqR), out: respW, diags: diags}
+	go g.serve()
+
+	c, err := lspclient.NewClient(dir, reqW, respR)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// TestLSPDiagnosticSourceConvertsPublishedDiagnostics feeds a fake gopls
+// an "undefined: pkg.sym" diagnostic on one file and a "declared and not
+// used" diagnostic on another, and checks LSPDiagnosticSource both
+// converts their 0-based LSP positions to this package's 1-based
+// Diagnostic and that the result still classifies correctly.
+func TestLSPDiagnosticSourceConvertsPublishedDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	undefinedPath := filepath.Join(dir, "main.go")
+	unusedPath := filepath.Join(dir, "helper.go")
+	if err := os.WriteFile(undefinedPath, []byte("package main\n\nfunc main() { strs.ToUpper(\"x\") }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unusedPath, []byte("package main\n\nfunc helper() { x := 1 }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	diags := map[string][]lspclient.Diagnostic{
+		fileURI(undefinedPath): {{
+			Range:   lspclient.Range{Start: lspclient.Position{Line: 2, Character: 14}, End: lspclient.Position{Line: 2, Character: 30}},
+			Message: "undefined: strs.ToUpper",
+			Source:  "compiler",
+		}},
+		fileURI(unusedPath): {{
+			Range:   lspclient.Range{Start: lspclient.Position{Line: 2, Character: 21}, End: lspclient.Position{Line: 2, Character: 22}},
+			Message: "x declared and not used",
+			Source:  "compiler",
+		}},
+	}
+
+	client := newFakeClient(t, dir, diags)
+	src := gofix.LSPDiagnosticSource{Client: client, Files: []string{undefinedPath, unusedPath}, Timeout: time.Second}
+
+	got, err := src.Diagnostics()
+	if err != nil {
+		t.Fatalf("Diagnostics: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %+v", len(got), got)
+	}
+
+	byFile := map[string]gofix.Diagnostic{}
+	for _, d := range got {
+		byFile[d.File] = d
+	}
+
+	undefinedDiag, ok := byFile[undefinedPath]
+	if !ok {
+		t.Fatalf("no diagnostic for %s: %+v", undefinedPath, got)
+	}
+	if undefinedDiag.Line != 3 || undefinedDiag.Col != 15 {
+		t.Errorf("undefined diagnostic position = %d:%d, want 3:15", undefinedDiag.Line, undefinedDiag.Col)
+	}
+	fix := gofix.Classify(undefinedDiag)
+	if fix.Category != gofix.CategoryUndeclaredName || fix.Package != "strs" || fix.Symbol != "ToUpper" {
+		t.Errorf("undefined diagnostic classified as %+v, want CategoryUndeclaredName strs.ToUpper", fix)
+	}
+
+	unusedDiag, ok := byFile[unusedPath]
+	if !ok {
+		t.Fatalf("no diagnostic for %s: %+v", unusedPath, got)
+	}
+	unusedFix := gofix.Classify(unusedDiag)
+	if unusedFix.Category != gofix.CategoryUnusedVar || unusedFix.Symbol != "x" {
+		t.Errorf("unused diagnostic classified as %+v, want CategoryUnusedVar x", unusedFix)
+	}
+}
+
+// TestLSPDiagnosticSourceWaitsOutTimeoutForSilentFile checks that a file
+// whose fake gopls never publishes diagnostics for (simulating a gopls
+// that hasn't finished analyzing yet) contributes no Diagnostics rather
+// than blocking Diagnostics forever.
+func TestLSPDiagnosticSourceWaitsOutTimeoutForSilentFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quiet.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newFakeClient(t, dir, nil)
+	src := gofix.LSPDiagnosticSource{Client: client, Files: []string{path}, Timeout: 50 * time.Millisecond}
+
+	got, err := src.Diagnostics()
+	if err != nil {
+		t.Fatalf("Diagnostics: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %v, want no diagnostics for a file gopls never published for", got)
+	}
+}
@@ -0,0 +1,354 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// MagicNumberFixer extracts unnamed integer and float literals into a
+// package-level const block, the way a reviewer would ask for in a code
+// review comment: "name this". It only extracts a literal that shows up
+// more than once in the file, or that appears directly in a comparison -
+// a one-off literal used to build a value (a buffer size, a slice index)
+// reads fine on its own and naming it would just add indirection. The
+// const's name comes from the identifier the literal is compared against
+// where one exists ("status == 404" -> statusNotFound... except
+// MagicNumberFixer has no notion of what 404 means, so it names it
+// statusValue404 instead of guessing English); a literal that only
+// qualifies by repetition, with no comparison to borrow a name from,
+// falls back to magicNumberN.
+//
+// It never looks inside an existing const declaration - an iota block's
+// values are already named by their constants, extracting from inside one
+// would fight the file's own numbering rather than clarify it - and it
+// never extracts 0, 1, or -1: a zero value, a single increment, and a
+// "not found" sentinel are idiomatic on their own, and a name for them
+// says nothing a reader doesn't already know. Struct tags aren't a
+// special case: a tag is a string literal, never an int or float one, so
+// MagicNumberFixer's Kind filter excludes them without having to look for
+// them specifically.
+type MagicNumberFixer struct{}
+
+// magicCandidate is every occurrence MagicNumberFixer found of one
+// distinct literal value (same sign, same token.Kind, same text) across a
+// file, plus what it would need to extract it: how many times it
+// repeated, whether any occurrence sat in a comparison, and the
+// identifier (if any) the first such comparison compared it against.
+type magicCandidate struct {
+	negative bool
+	kind     token.Token
+	text     string // BasicLit.Value, unsigned
+	count    int
+	compared bool
+	context  string
+	firstPos token.Pos
+}
+
+func (c *magicCandidate) key() string {
+	sign := ""
+	if c.negative {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s|%s%s", c.kind, sign, c.text)
+}
+
+func (c *magicCandidate) signedText() string {
+	if c.negative {
+		return "-" + c.text
+	}
+	return c.text
+}
+
+// Apply extracts every magic number it finds in file into a trailing
+// const block, rewriting every usage site to reference it. It reports
+// whether it changed anything.
+func (MagicNumberFixer) Apply(fset *token.FileSet, file *ast.File) (bool, error) {
+	candidates := collectMagicCandidates(file)
+
+	var qualifying []*magicCandidate
+	for _, c := range candidates {
+		if isGenericLiteral(c) {
+			continue
+		}
+		if c.count > 1 || c.compared {
+			qualifying = append(qualifying, c)
+		}
+	}
+	if len(qualifying) == 0 {
+		return false, nil
+	}
+	sort.Slice(qualifying, func(i, j int) bool { return qualifying[i].firstPos < qualifying[j].firstPos })
+
+	names := map[string]string{}
+	used := map[string]bool{}
+	for i, c := range qualifying {
+		names[c.key()] = constNameFor(file, c, i, used)
+	}
+
+	replaceMagicNumbers(file, names)
+	addMagicNumberConsts(file, qualifying, names)
+	return true, nil
+}
+
+// isGenericLiteral reports whether c is one of the literals MagicNumberFixer
+// always leaves alone regardless of how often it repeats or where it's
+// compared.
+func isGenericLiteral(c *magicCandidate) bool {
+	switch c.signedText() {
+	case "0", "1", "-1":
+		return true
+	default:
+		return false
+	}
+}
+
+// collectMagicCandidates walks every top-level declaration in file except
+// const blocks, grouping every int/float literal it finds by its exact
+// signed text.
+func collectMagicCandidates(file *ast.File) []*magicCandidate {
+	byKey := map[string]*magicCandidate{}
+	var order []string
+
+	record := func(negative bool, lit *ast.BasicLit, compared bool, context string) {
+		c := &magicCandidate{negative: negative, kind: lit.Kind, text: lit.Value}
+		k := c.key()
+		existing, ok := byKey[k]
+		if !ok {
+			c.firstPos = lit.Pos()
+			byKey[k] = c
+			order = append(order, k)
+			existing = c
+		}
+		existing.count++
+		if compared && !existing.compared {
+			existing.compared = true
+			existing.context = context
+		}
+	}
+
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.CONST {
+			continue
+		}
+		astutil.Apply(decl, func(c *astutil.Cursor) bool {
+			if u, ok := c.Node().(*ast.UnaryExpr); ok && u.Op == token.SUB {
+				if lit, ok := u.X.(*ast.BasicLit); ok && isNumericLit(lit) {
+					compared, context := comparisonContext(c.Parent(), c.Node())
+					record(true, lit, compared, context)
+					return false
+				}
+			}
+			if lit, ok := c.Node().(*ast.BasicLit); ok && isNumericLit(lit) {
+				compared, context := comparisonContext(c.Parent(), c.Node())
+				record(false, lit, compared, context)
+			}
+			return true
+		}, nil)
+	}
+
+	candidates := make([]*magicCandidate, 0, len(order))
+	for _, k := range order {
+		candidates = append(candidates, byKey[k])
+	}
+	return candidates
+}
+
+// isNumericLit reports whether lit is an integer or floating-point
+// literal, as opposed to a string (which is how a struct tag is
+// represented - MagicNumberFixer never has to special-case tags because
+// they never satisfy this check), rune, or imaginary literal.
+func isNumericLit(lit *ast.BasicLit) bool {
+	return lit.Kind == token.INT || lit.Kind == token.FLOAT
+}
+
+// comparisonContext reports whether parent is a comparison
+// (==, !=, <, <=, >, >=) with self as one of its two operands, and if so
+// the name of the identifier or selector self was compared against.
+func comparisonContext(parent, self ast.Node) (bool, string) {
+	bin, ok := parent.(*ast.BinaryExpr)
+	if !ok || !isComparisonOp(bin.Op) {
+		return false, ""
+	}
+	other := bin.Y
+	if bin.Y == self {
+		other = bin.X
+	}
+	switch x := other.(type) {
+	case *ast.Ident:
+		return true, x.Name
+	case *ast.SelectorExpr:
+		return true, x.Sel.Name
+	default:
+		return true, ""
+	}
+}
+
+func isComparisonOp(op token.Token) bool {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return true
+	default:
+		return false
+	}
+}
+
+// constNameFor derives an unexported const name for c, preferring its
+// comparison context ("status" for status == 404) over the generic
+// magicNumberN fallback, and disambiguates against both file's existing
+// identifiers and names already handed out earlier in this same pass.
+func constNameFor(file *ast.File, c *magicCandidate, index int, used map[string]bool) string {
+	base := fmt.Sprintf("magicNumber%d", index+1)
+	if c.context != "" {
+		base = lowerFirst(c.context) + "Value" + literalSuffix(c)
+	}
+
+	name := base
+	for n := 2; declaresIdent(file, name) || used[name]; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	used[name] = true
+	return name
+}
+
+// literalSuffix renders c's value as an identifier-safe suffix: digits
+// pass through unchanged, "." becomes "Pt" (Go identifiers can't contain
+// a literal dot), and a negative value is prefixed "Neg".
+func literalSuffix(c *magicCandidate) string {
+	s := strings.ReplaceAll(c.text, ".", "Pt")
+	s = strings.ReplaceAll(s, "_", "")
+	if c.negative {
+		s = "Neg" + s
+	}
+	return s
+}
+
+// lowerFirst lowercases s's first rune, leaving the rest untouched -
+// "Status" and "status" both become "status".
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// replaceMagicNumbers rewrites every occurrence of a literal keyed in
+// names, in place, to an *ast.Ident referencing its extracted const.
+func replaceMagicNumbers(file *ast.File, names map[string]string) {
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.CONST {
+			continue
+		}
+		astutil.Apply(decl, func(c *astutil.Cursor) bool {
+			if u, ok := c.Node().(*ast.UnaryExpr); ok && u.Op == token.SUB {
+				if lit, ok := u.X.(*ast.BasicLit); ok && isNumericLit(lit) {
+					if name, ok := names[(&magicCandidate{negative: true, kind: lit.Kind, text: lit.Value}).key()]; ok {
+						c.Replace(ast.NewIdent(name))
+					}
+					return false
+				}
+			}
+			if lit, ok := c.Node().(*ast.BasicLit); ok && isNumericLit(lit) {
+				if name, ok := names[(&magicCandidate{kind: lit.Kind, text: lit.Value}).key()]; ok {
+					c.Replace(ast.NewIdent(name))
+				}
+			}
+			return true
+		}, nil)
+	}
+}
+
+// addMagicNumberConsts appends `const ( name = value; ... )` to the end of
+// file's declarations, one spec per qualifying candidate in the order
+// Apply extracted them. It's appended at the end for the same reason
+// addErrOutOfBounds is: a brand-new declaration built from NoPos nodes has
+// no source position go/printer can use to order it against the file's
+// existing comments, and appending after everything real sidesteps that.
+func addMagicNumberConsts(file *ast.File, qualifying []*magicCandidate, names map[string]string) {
+	specs := make([]ast.Spec, 0, len(qualifying))
+	for _, c := range qualifying {
+		var value ast.Expr = &ast.BasicLit{Kind: c.kind, Value: c.text}
+		if c.negative {
+			value = &ast.UnaryExpr{Op: token.SUB, X: value.(*ast.BasicLit)}
+		}
+		specs = append(specs, &ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent(names[c.key()])},
+			Values: []ast.Expr{value},
+		})
+	}
+	file.Decls = append(file.Decls, &ast.GenDecl{Tok: token.CONST, Lparen: 1, Specs: specs})
+}
+
+// RunMagicNumberPass runs MagicNumberFixer over each file in paths,
+// writing back any file it changes. Structured the same transactional way
+// as RunBoundsCheckPass/RunDeprecatedAPIPass.
+func RunMagicNumberPass(paths []string) ([]patch.Result, error) {
+	files := map[string]bool{}
+	for _, p := range paths {
+		files[p] = true
+	}
+	txnFiles := make([]string, 0, len(files))
+	for f := range files {
+		txnFiles = append(txnFiles, f)
+	}
+	txn, err := patch.Begin(txnFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []patch.Result
+	fixer := MagicNumberFixer{}
+	for file := range files {
+		before := txn.Read(file)
+
+		fset := token.NewFileSet()
+		fileAST, err := parser.ParseFile(fset, file, before, parser.ParseComments)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+
+		changed, err := fixer.Apply(fset, fileAST)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+
+		if err := FormatImports(fset, fileAST, modulePathOf(filepath.Dir(file))); err != nil {
+			_ = txn.Rollback()
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, fileAST); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+
+		bs, be, as, ae := patch.DiffLines(before, patched)
+		txn.Write(file, patched)
+		results = append(results, patch.Result{
+			File: file, Kind: patch.KindExtractConstant, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package gofix
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestMagicNumberFixerExtractsRepeatedAndComparedLiterals(t *testing.T) {
+	const src = `package p
+
+import "fmt"
+
+const (
+	StateIdle = iota
+	StateRunning
+)
+
+func checkStatus(status int) string {
+	if status == 404 {
+		return "not found"
+	}
+	return fmt.Sprintf("%d", status)
+}
+
+func price() float64 {
+	a := 9.99
+	b := 9.99
+	return a + b
+}
+
+func timeout() int {
+	return -5
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (MagicNumberFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"if status == statusValue404 {",
+		"a := magicNumber",
+		"b := magicNumber",
+		"= 404",
+		"= 9.99",
+		"StateIdle = iota",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got:\n%s\nwant %q", out, want)
+		}
+	}
+	if !strings.Contains(out, "return -5") {
+		t.Errorf("got:\n%s\nwant the single, uncompared -5 literal left untouched", out)
+	}
+}
+
+func TestMagicNumberFixerIgnoresGenericLiterals(t *testing.T) {
+	const src = `package p
+
+func values() (int, int, int) {
+	a := 0
+	b := 1
+	c := -1
+	a = a
+	b = b
+	c = c
+	return a, b, c
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (MagicNumberFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: 0, 1, -1 are never extracted")
+	}
+}
+
+func TestMagicNumberFixerLeavesConstBlockAlone(t *testing.T) {
+	const src = `package p
+
+const (
+	maxRetries = 3
+	minRetries = 3
+)
+
+func f() int {
+	return maxRetries
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (MagicNumberFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: the repeated 3s are inside an existing const block")
+	}
+}
+
+func TestMagicNumberFixerLeavesStructTagsAlone(t *testing.T) {
+	const src = `package p
+
+type Resp struct {
+	Code int ` + "`json:\"code,omitempty\" example:\"404\"`" + `
+}
+
+func isNotFound(c int) bool {
+	return c == 404
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (MagicNumberFixer{}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one: c == 404 is a comparison")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `example:"404"`) {
+		t.Errorf("got:\n%s\nwant the struct tag's 404 left untouched", out)
+	}
+	if strings.Contains(out, "c == 404") {
+		t.Errorf("got:\n%s\nwant the comparison's 404 extracted", out)
+	}
+}
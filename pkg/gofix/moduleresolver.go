@@ -0,0 +1,132 @@
+package gofix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ModuleResolver finds import paths, outside the standard library and the
+// current module, that could satisfy a missing-import Fix. The default
+// GoListResolver searches the module's own dependency graph (go.sum) rather
+// than the much larger universe of all published modules, since that's the
+// only set of packages buildable without first editing go.mod.
+type ModuleResolver interface {
+	// Candidates returns the import paths of required modules whose root
+	// package is named pkgName. Go's "undefined: pkg" diagnostic only ever
+	// names the bare identifier at the missing import's use site, never a
+	// specific member read off it, so pkgName is a package name, not an
+	// exported symbol of one.
+	Candidates(pkgName string) ([]string, error)
+}
+
+// GoListResolver is the default ModuleResolver. It shells out to `go list -m
+// -json all` to enumerate the current module's required modules (honoring
+// any replace directives) and checks each one's root directory for a
+// package declaration matching pkgName. A replace directive pointing at a
+// local filesystem path is also checked directly, even when nothing
+// requires that module yet: `go list -m all` only reports modules already
+// in the build list, but a missing `require` is exactly the case
+// GoModPatcher exists to fix, so its target must be resolvable first.
+type GoListResolver struct {
+	// ModDir is the directory containing the go.mod whose dependencies
+	// should be searched.
+	ModDir string
+}
+
+// goListModule mirrors the subset of `go list -m -json` output this package
+// cares about.
+type goListModule struct {
+	Path string
+	Dir  string
+	Main bool
+}
+
+func (r GoListResolver) Candidates(pkgName string) ([]string, error) {
+	seen := map[string]bool{}
+	var candidates []string
+
+	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd.Dir = r.ModDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list -m: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var m goListModule
+		if err := dec.Decode(&m); err != nil {
+			break
+		}
+		if m.Main || m.Dir == "" || seen[m.Path] {
+			continue
+		}
+		if packageNamed(m.Dir, pkgName) {
+			seen[m.Path] = true
+			candidates = append(candidates, m.Path)
+		}
+	}
+
+	for path, dir := range r.localReplaceTargets() {
+		if seen[path] {
+			continue
+		}
+		if packageNamed(dir, pkgName) {
+			seen[path] = true
+			candidates = append(candidates, path)
+		}
+	}
+
+	return candidates, nil
+}
+
+// localReplaceTargets reads r.ModDir's go.mod and returns the module path
+// and resolved directory of every replace directive whose target is a
+// local filesystem path (as opposed to another module@version).
+func (r GoListResolver) localReplaceTargets() map[string]string {
+	data, err := os.ReadFile(filepath.Join(r.ModDir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+	f, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil
+	}
+
+	targets := map[string]string{}
+	for _, rep := range f.Replace {
+		if rep.New.Version != "" {
+			continue // replace target is a module@version, not a local path
+		}
+		targets[rep.Old.Path] = filepath.Join(r.ModDir, rep.New.Path)
+	}
+	return targets
+}
+
+// packageNamed reports whether dir's root package - not any subdirectory -
+// declares `package name`.
+func packageNamed(dir, name string) bool {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return false
+	}
+	fset := token.NewFileSet()
+	for _, path := range matches {
+		f, err := parser.ParseFile(fset, path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		if f.Name.Name == name {
+			return true
+		}
+	}
+	return false
+}
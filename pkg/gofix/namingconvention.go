@@ -0,0 +1,275 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// NamingConventionFixer renames package-level identifiers that violate
+// Go's initialism convention - HTTPSUrl should be HTTPSURL, Id should be
+// ID - to their canonical form, then rewrites every reference to the
+// renamed identifier across the package. lintName and commonInitialisms
+// below reimplement golang.org/x/lint/golint's identically-named,
+// unexported equivalents: golint only exposes its linter as a list of
+// Problems to print, never the name-correction logic itself, so there's
+// no exported API to call into for this.
+type NamingConventionFixer struct {
+	// AllowExported permits renaming an exported identifier. Off by
+	// default - renaming one is an API break for every importer of this
+	// package, a risk an unexported identifier's rename never carries.
+	AllowExported bool
+}
+
+// Apply renames every non-idiomatic package-level identifier it finds
+// across files - which must all share fset and belong to a single
+// package - rewriting every reference, not just the declaration. It uses
+// go/types to resolve each *ast.Ident to the object it refers to rather
+// than matching on name alone, so a same-named identifier in an unrelated
+// scope (a local variable shadowing a renamed package-level one) is never
+// touched - the same scope-awareness gorename's type-checked rename
+// relies on. It reports whether it changed anything.
+func (fx NamingConventionFixer) Apply(fset *token.FileSet, files []*ast.File) (changed bool, err error) {
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+		Uses: map[*ast.Ident]types.Object{},
+	}
+	cfg := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	pkgName := "pkg"
+	if len(files) > 0 {
+		pkgName = files[0].Name.Name
+	}
+	// A scenario's starting repo is often intentionally broken, so a type
+	// error here is expected, not fatal - info.Defs/Uses still end up
+	// populated for every identifier the checker got far enough to
+	// resolve, which is all Apply needs.
+	_, _ = cfg.Check(pkgName, fset, files, info)
+
+	renames := map[types.Object]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			for _, ident := range packageLevelIdents(decl) {
+				obj := info.Defs[ident]
+				if obj == nil || ident.Name == "_" {
+					continue
+				}
+				if ast.IsExported(ident.Name) && !fx.AllowExported {
+					continue
+				}
+				if want := lintName(ident.Name); want != ident.Name {
+					renames[obj] = want
+				}
+			}
+		}
+	}
+	if len(renames) == 0 {
+		return false, nil
+	}
+
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj := info.Defs[ident]
+			if obj == nil {
+				obj = info.Uses[ident]
+			}
+			if obj == nil {
+				return true
+			}
+			if want, ok := renames[obj]; ok && ident.Name != want {
+				ident.Name = want
+				changed = true
+			}
+			return true
+		})
+	}
+	return changed, nil
+}
+
+// packageLevelIdents returns the identifiers decl introduces at package
+// scope: a function or method's name, a type's name, or a var/const
+// spec's names. Local variables inside function bodies are deliberately
+// out of scope - they're never visible outside the function that
+// declares them, so renaming one is not "a reference within the package"
+// the way a package-level identifier's is.
+func packageLevelIdents(decl ast.Decl) []*ast.Ident {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return []*ast.Ident{d.Name}
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return nil
+		}
+		var idents []*ast.Ident
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				idents = append(idents, s.Name)
+			case *ast.ValueSpec:
+				idents = append(idents, s.Names...)
+			}
+		}
+		return idents
+	default:
+		return nil
+	}
+}
+
+// RunNamingConventionPass runs NamingConventionFixer over every file in
+// paths, which must all belong to the same package - a cross-file rename
+// can only resolve references correctly when go/types sees the whole
+// package at once. It's structured the same transactional way as
+// RunSSRPass/RunDeprecatedAPIPass: every file in a run is staged in a
+// single patch.Transaction and only committed once the whole pass
+// succeeds.
+func RunNamingConventionPass(paths []string, allowExported bool) ([]patch.Result, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	files := map[string]bool{}
+	for _, p := range paths {
+		files[p] = true
+	}
+	txnFiles := make([]string, 0, len(files))
+	for f := range files {
+		txnFiles = append(txnFiles, f)
+	}
+	txn, err := patch.Begin(txnFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	before := make(map[string][]byte, len(txnFiles))
+	asts := make(map[string]*ast.File, len(txnFiles))
+	var pkgFiles []*ast.File
+	for _, file := range txnFiles {
+		src := txn.Read(file)
+		before[file] = src
+		fileAST, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		asts[file] = fileAST
+		pkgFiles = append(pkgFiles, fileAST)
+	}
+
+	fixer := NamingConventionFixer{AllowExported: allowExported}
+	if _, err := fixer.Apply(fset, pkgFiles); err != nil {
+		_ = txn.Rollback()
+		return nil, err
+	}
+
+	var results []patch.Result
+	for _, file := range txnFiles {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, asts[file]); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+		if bytes.Equal(patched, before[file]) {
+			continue
+		}
+
+		bs, be, as, ae := patch.DiffLines(before[file], patched)
+		txn.Write(file, patched)
+		results = append(results, patch.Result{
+			File: file, Kind: patch.KindRename, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
+
+// lintName returns name's canonical form per Go's naming convention -
+// initialisms kept all-uppercase (Id -> ID, Url -> URL), camelCase words
+// otherwise left alone - or name unchanged if it's already idiomatic.
+// This is a direct port of golang.org/x/lint/golint's unexported lintName
+// function; see NamingConventionFixer's doc comment for why it's ported
+// rather than imported.
+func lintName(name string) (should string) {
+	if name == "_" {
+		return name
+	}
+	allLower := true
+	for _, r := range name {
+		if !unicode.IsLower(r) {
+			allLower = false
+			break
+		}
+	}
+	if allLower {
+		return name
+	}
+
+	runes := []rune(name)
+	w, i := 0, 0
+	for i+1 <= len(runes) {
+		eow := false
+		if i+1 == len(runes) {
+			eow = true
+		} else if runes[i+1] == '_' {
+			eow = true
+			n := 1
+			for i+n+1 < len(runes) && runes[i+n+1] == '_' {
+				n++
+			}
+			if i+n+1 < len(runes) && unicode.IsDigit(runes[i]) && unicode.IsDigit(runes[i+n+1]) {
+				n--
+			}
+			copy(runes[i+1:], runes[i+n+1:])
+			runes = runes[:len(runes)-n]
+		} else if unicode.IsLower(runes[i]) && !unicode.IsLower(runes[i+1]) {
+			eow = true
+		}
+		i++
+		if !eow {
+			continue
+		}
+
+		word := string(runes[w:i])
+		if u := strings.ToUpper(word); commonInitialisms[u] {
+			if w == 0 && unicode.IsLower(runes[w]) {
+				u = strings.ToLower(u)
+			}
+			copy(runes[w:], []rune(u))
+		} else if w > 0 && strings.ToLower(word) == word {
+			runes[w] = unicode.ToUpper(runes[w])
+		}
+		w = i
+	}
+	return string(runes)
+}
+
+// commonInitialisms is golint's set of common initialisms, ported
+// verbatim: only entries highly unlikely to be non-initialisms belong
+// here (e.g. "ID" is fine, "AND" is not).
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true,
+	"QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
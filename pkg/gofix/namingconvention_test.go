@@ -0,0 +1,203 @@
+package gofix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func parsePackage(t *testing.T, fset *token.FileSet, srcs map[string]string) []*ast.File {
+	t.Helper()
+	var files []*ast.File
+	for name, src := range srcs {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("ParseFile %s: %v", name, err)
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+func renderAll(t *testing.T, fset *token.FileSet, files []*ast.File) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	for _, f := range files {
+		var buf strings.Builder
+		if err := format.Node(&buf, fset, f); err != nil {
+			t.Fatalf("format.Node: %v", err)
+		}
+		out[fset.File(f.Pos()).Name()] = buf.String()
+	}
+	return out
+}
+
+func TestNamingConventionFixerRenamesUnexportedAcrossFiles(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+var userId = 7
+
+func getUrl() string {
+	return "x"
+}
+`,
+		"b.go": `package p
+
+func printUserId() {
+	println(userId, getUrl())
+}
+`,
+	})
+
+	changed, err := (NamingConventionFixer{}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	out := renderAll(t, fset, files)
+	if strings.Contains(out["a.go"], "userId") || !strings.Contains(out["a.go"], "userID") {
+		t.Errorf("a.go: userId not renamed to userID:\n%s", out["a.go"])
+	}
+	if !strings.Contains(out["a.go"], "getURL") {
+		t.Errorf("a.go: getUrl not renamed to getURL:\n%s", out["a.go"])
+	}
+	if strings.Contains(out["b.go"], "userId") || !strings.Contains(out["b.go"], "println(userID, getURL())") {
+		t.Errorf("b.go: reference not rewritten alongside declaration:\n%s", out["b.go"])
+	}
+}
+
+func TestNamingConventionFixerLeavesExportedIdentifiersAloneByDefault(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+var UserId = 7
+`,
+	})
+
+	changed, err := (NamingConventionFixer{}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: UserId is exported and AllowExported is false")
+	}
+}
+
+func TestNamingConventionFixerRenamesExportedWhenAllowed(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+var UserId = 7
+`,
+	})
+
+	changed, err := (NamingConventionFixer{AllowExported: true}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	out := renderAll(t, fset, files)
+	if !strings.Contains(out["a.go"], "UserID") {
+		t.Errorf("UserId not renamed to UserID:\n%s", out["a.go"])
+	}
+}
+
+func TestNamingConventionFixerIgnoresLocalVariables(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+func f() int {
+	localId := 3
+	return localId
+}
+`,
+	})
+
+	changed, err := (NamingConventionFixer{}).Apply(fset, files)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Fatal("reported a change, want none: localId is function-local, not package scope")
+	}
+}
+
+// stubGolint stands in for shelling out to the real golint binary: golint
+// itself is unmaintained and not guaranteed to be on PATH, and no other
+// test in this repo depends on an external non-go-toolchain binary, so
+// this reimplements just enough of golint's naming check - walk every
+// package-level identifier and flag any whose name disagrees with
+// lintName - to assert the fixer's output would pass a real golint run.
+func stubGolint(fset *token.FileSet, files []*ast.File) []string {
+	var problems []string
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			for _, ident := range packageLevelIdents(decl) {
+				if ident.Name != "_" && lintName(ident.Name) != ident.Name {
+					problems = append(problems, fmt.Sprintf("%s: should be %s", ident.Name, lintName(ident.Name)))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+func TestNamingConventionFixerOutputPassesGolint(t *testing.T) {
+	fset := token.NewFileSet()
+	files := parsePackage(t, fset, map[string]string{
+		"a.go": `package p
+
+var userId = 7
+var serverIp = "127.0.0.1"
+
+func getUrl() string {
+	return "x"
+}
+`,
+	})
+
+	if problems := stubGolint(fset, files); len(problems) == 0 {
+		t.Fatal("expected stubGolint to flag problems before fixing")
+	}
+
+	if _, err := (NamingConventionFixer{}).Apply(fset, files); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if problems := stubGolint(fset, files); len(problems) != 0 {
+		t.Errorf("fixed output still fails stubGolint: %v", problems)
+	}
+}
+
+func TestLintName(t *testing.T) {
+	cases := map[string]string{
+		"Id":         "ID",
+		"Url":        "URL",
+		"getURL":     "getURL",
+		"getHTTP":    "getHTTP",
+		"userId":     "userID",
+		"already_ok": "alreadyOk",
+		"_":          "_",
+		"lowercase":  "lowercase",
+	}
+	for in, want := range cases {
+		if got := lintName(in); got != want {
+			t.Errorf("lintName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package gofix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// SplitSuggestion is one candidate package PackageSizeSuggester proposes
+// carving out of an oversized package: the exported identifiers it
+// clusters together, the files that declare them, and a name for the new
+// package. It is advisory only - PackageSizeSuggester never rewrites any
+// file, since deciding how to split a package's public API is a judgment
+// call a human should make, not something safe to auto-apply.
+type SplitSuggestion struct {
+	NewPackageName string
+	Files          []string
+	Exports        []string
+}
+
+// PackageSizeThresholds configures when PackageSizeSuggester considers a
+// package too large to keep as a single unit. A zero value for either
+// field falls back to that field's default.
+type PackageSizeThresholds struct {
+	// MaxExportedTypes is the number of exported types a package may
+	// declare before a split is suggested. Zero means the default of 20.
+	MaxExportedTypes int
+	// MaxExportedFunctions is the number of exported functions a package
+	// may declare (methods don't count separately - they travel with
+	// their receiver type) before a split is suggested. Zero means the
+	// default of 50.
+	MaxExportedFunctions int
+}
+
+func (t PackageSizeThresholds) maxTypes() int {
+	if t.MaxExportedTypes > 0 {
+		return t.MaxExportedTypes
+	}
+	return 20
+}
+
+func (t PackageSizeThresholds) maxFunctions() int {
+	if t.MaxExportedFunctions > 0 {
+		return t.MaxExportedFunctions
+	}
+	return 50
+}
+
+// PackageSizeSuggester analyzes a package's exported surface and, once it
+// exceeds Thresholds, proposes how it could be split into smaller
+// packages. Exported types and exported top-level functions are the
+// nodes of a dependency graph - an edge is drawn between two nodes
+// whenever one's declaration references the other's name - and each
+// connected component becomes one SplitSuggestion, on the theory that
+// moving one node to a new package without the other it depends on would
+// just recreate the same coupling across a package boundary.
+type PackageSizeSuggester struct {
+	Thresholds PackageSizeThresholds
+}
+
+// exportNode is one exported type or top-level function PackageSizeSuggester
+// tracks as a node in its dependency graph.
+type exportNode struct {
+	name    string
+	file    string
+	methods []string // method names, populated only for a type node
+}
+
+// Analyze parses every file in paths - which must belong to a single
+// package - and returns the split suggestions for it, or nil if the
+// package is within Thresholds.
+func (s PackageSizeSuggester) Analyze(paths []string) ([]SplitSuggestion, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	fileOf := map[*ast.File]string{}
+	for _, p := range paths {
+		f, err := parser.ParseFile(fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		files = append(files, f)
+		fileOf[f] = p
+	}
+
+	nodes := map[string]*exportNode{}
+	var typeCount, funcCount int
+	for _, file := range files {
+		path := fileOf[file]
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || !ts.Name.IsExported() {
+						continue
+					}
+					typeCount++
+					nodes[ts.Name.Name] = &exportNode{name: ts.Name.Name, file: path}
+				}
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() {
+					continue
+				}
+				if d.Recv != nil {
+					if recvName := receiverTypeName(d.Recv); recvName != "" {
+						if n, ok := nodes[recvName]; ok {
+							n.methods = append(n.methods, d.Name.Name)
+						}
+					}
+					continue
+				}
+				funcCount++
+				nodes[d.Name.Name] = &exportNode{name: d.Name.Name, file: path}
+			}
+		}
+	}
+
+	if typeCount <= s.Thresholds.maxTypes() && funcCount <= s.Thresholds.maxFunctions() {
+		return nil, nil
+	}
+
+	uf := newUnionFind()
+	for name := range nodes {
+		uf.add(name)
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			owner := ""
+			if fn.Recv != nil {
+				owner = receiverTypeName(fn.Recv)
+			} else if fn.Name.IsExported() {
+				owner = fn.Name.Name
+			}
+			if owner == "" || nodes[owner] == nil {
+				continue
+			}
+			ast.Inspect(fn, func(n ast.Node) bool {
+				ident, ok := n.(*ast.Ident)
+				if !ok || ident.Name == owner {
+					return true
+				}
+				if nodes[ident.Name] != nil {
+					uf.union(owner, ident.Name)
+				}
+				return true
+			})
+		}
+	}
+
+	clusters := map[string][]string{}
+	for name := range nodes {
+		root := uf.find(name)
+		clusters[root] = append(clusters[root], name)
+	}
+
+	var roots []string
+	for root := range clusters {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+
+	var suggestions []SplitSuggestion
+	for _, root := range roots {
+		members := clusters[root]
+		sort.Strings(members)
+
+		fileSet := map[string]bool{}
+		var exports []string
+		for _, m := range members {
+			node := nodes[m]
+			fileSet[node.file] = true
+			exports = append(exports, m)
+			exports = append(exports, node.methods...)
+		}
+		sort.Strings(exports)
+
+		var fileList []string
+		for f := range fileSet {
+			fileList = append(fileList, f)
+		}
+		sort.Strings(fileList)
+
+		suggestions = append(suggestions, SplitSuggestion{
+			NewPackageName: strings.ToLower(members[0]),
+			Files:          fileList,
+			Exports:        exports,
+		})
+	}
+	return suggestions, nil
+}
+
+// receiverTypeName returns the name of the type a method receiver binds
+// to, stripping a leading pointer star, or "" if recv isn't a plain
+// (possibly pointer) named-type receiver.
+func receiverTypeName(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) == 0 {
+		return ""
+	}
+	expr := recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name
+}
+
+// unionFind is a disjoint-set over export names, used to group
+// PackageSizeSuggester's dependency graph into connected components.
+type unionFind struct {
+	parent map[string]string
+}
+
+func newUnionFind() *unionFind {
+	return &unionFind{parent: map[string]string{}}
+}
+
+func (u *unionFind) add(x string) {
+	if _, ok := u.parent[x]; !ok {
+		u.parent[x] = x
+	}
+}
+
+func (u *unionFind) find(x string) string {
+	if u.parent[x] != x {
+		u.parent[x] = u.find(u.parent[x])
+	}
+	return u.parent[x]
+}
+
+func (u *unionFind) union(a, b string) {
+	ra, rb := u.find(a), u.find(b)
+	if ra != rb {
+		u.parent[ra] = rb
+	}
+}
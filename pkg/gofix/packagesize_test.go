@@ -0,0 +1,127 @@
+package gofix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile is a small t.TempDir helper shared by the tests below.
+func writeFile(t *testing.T, dir, name, src string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("WriteFile %s: %v", path, err)
+	}
+	return path
+}
+
+func TestPackageSizeSuggesterWithinThresholdsReportsNothing(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "a.go", `package p
+
+type Widget struct{}
+
+func NewWidget() *Widget { return &Widget{} }
+`)
+
+	suggestions, err := (PackageSizeSuggester{}).Analyze([]string{path})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if suggestions != nil {
+		t.Errorf("got %v, want nil: package is well within the default thresholds", suggestions)
+	}
+}
+
+// TestPackageSizeSuggesterClustersBySynthesizedLargePackage builds a
+// synthetic package with 60 exported functions - 10 more than the
+// default 50-function threshold - split across two unrelated clusters:
+// Widget/NewWidget/(*Widget).Use and Gadget/NewGadget/(*Gadget).Use, each
+// paired with its own disjoint group of standalone helper functions. It
+// asserts the suggester both fires (the package is oversized) and keeps
+// the two clusters separate (nothing ties a Widget helper to a Gadget
+// one).
+func TestPackageSizeSuggesterClustersBySynthesizedLargePackage(t *testing.T) {
+	dir := t.TempDir()
+
+	var b strings.Builder
+	b.WriteString("package p\n\n")
+	b.WriteString("type Widget struct{}\n\n")
+	b.WriteString("func NewWidget() *Widget { return &Widget{} }\n\n")
+	b.WriteString("func (w *Widget) Use() { WidgetHelper0(w) }\n\n")
+	for i := 0; i < 28; i++ {
+		fmt.Fprintf(&b, "func WidgetHelper%d(w *Widget) { _ = w }\n\n", i)
+	}
+	path1 := writeFile(t, dir, "widget.go", b.String())
+
+	b.Reset()
+	b.WriteString("package p\n\n")
+	b.WriteString("type Gadget struct{}\n\n")
+	b.WriteString("func NewGadget() *Gadget { return &Gadget{} }\n\n")
+	b.WriteString("func (g *Gadget) Use() { GadgetHelper0(g) }\n\n")
+	for i := 0; i < 28; i++ {
+		fmt.Fprintf(&b, "func GadgetHelper%d(g *Gadget) { _ = g }\n\n", i)
+	}
+	path2 := writeFile(t, dir, "gadget.go", b.String())
+
+	suggestions, err := (PackageSizeSuggester{}).Analyze([]string{path1, path2})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("got %d suggestions, want 2 (Widget cluster, Gadget cluster): %+v", len(suggestions), suggestions)
+	}
+
+	var widget, gadget *SplitSuggestion
+	for i := range suggestions {
+		switch {
+		case contains(suggestions[i].Exports, "NewWidget"):
+			widget = &suggestions[i]
+		case contains(suggestions[i].Exports, "NewGadget"):
+			gadget = &suggestions[i]
+		}
+	}
+	if widget == nil || gadget == nil {
+		t.Fatalf("expected one Widget cluster and one Gadget cluster, got %+v", suggestions)
+	}
+	if contains(widget.Exports, "NewGadget") || contains(gadget.Exports, "NewWidget") {
+		t.Errorf("clusters bled into each other: widget=%v gadget=%v", widget.Exports, gadget.Exports)
+	}
+	if !contains(widget.Exports, "Use") {
+		t.Errorf("Widget cluster missing its Use method: %v", widget.Exports)
+	}
+	if widget.Files[0] != path1 {
+		t.Errorf("Widget cluster Files = %v, want [%s]", widget.Files, path1)
+	}
+}
+
+func TestPackageSizeSuggesterRespectsCustomThresholds(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "a.go", `package p
+
+type A struct{}
+type B struct{}
+type C struct{}
+`)
+
+	s := PackageSizeSuggester{Thresholds: PackageSizeThresholds{MaxExportedTypes: 2}}
+	suggestions, err := s.Analyze([]string{path})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(suggestions) == 0 {
+		t.Fatal("got no suggestions, want some: 3 types exceeds the custom threshold of 2")
+	}
+}
+
+func contains(ss []string, want string) bool {
+	for _, s := range ss {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
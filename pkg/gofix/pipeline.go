@@ -0,0 +1,103 @@
+package gofix
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/FernandoTN/repo-patcher/pkg/astedit"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// Candidate is a single proposed patch for one Fix, competing against
+// however many other candidates (gofix-sourced or LLM-sourced) address the
+// same diagnostic. The pipeline ranks candidates by Confidence and lets the
+// caller apply the winner. Result is the zero value for LLM-sourced
+// candidates, which don't go through a gofix Propose* function.
+type Candidate struct {
+	Fix        Fix
+	Patch      []byte
+	Source     string // "gofix" or "llm"
+	Confidence float64
+	Result     patch.Result
+}
+
+// Propose runs every deterministic gofix pass over src for fix and returns
+// whatever candidates it can produce. dir is the package directory (used
+// for symbol resolution and, when fixGoMod is set, as the go.mod to patch)
+// and mode controls unused-import/var handling. Callers append their own
+// LLM-sourced Candidates to the result and pass the combined slice to Rank.
+func Propose(src []byte, fix Fix, idx *SymbolIndex, dir string, mode UnusedMode, fixGoMod bool) ([]Candidate, error) {
+	var candidates []Candidate
+	add := func(patched []byte, confidence float64, result patch.Result) {
+		// A patch identical to src fixes nothing; never let a no-op
+		// compete with (and possibly outrank, on a tie) a real edit.
+		if bytes.Equal(patched, src) {
+			return
+		}
+		candidates = append(candidates, Candidate{Fix: fix, Patch: patched, Source: "gofix", Confidence: confidence, Result: result})
+	}
+
+	if out, results, err := ProposeMissingImportFix(src, fix, idx, ImportFixOptions{ModDir: dir, FixGoMod: fixGoMod}); err != nil {
+		return nil, err
+	} else if len(results) > 0 {
+		add(out, 1.0, results[0])
+	}
+
+	if out, results, err := ProposeUnusedFix(src, fix, mode); err != nil {
+		return nil, err
+	} else if len(results) > 0 {
+		add(out, 1.0, results[0])
+	}
+
+	if out, results, err := ProposeTypeMismatchFix(src, fix); err != nil {
+		return nil, err
+	} else if len(results) > 0 {
+		add(out, 1.0, results[0])
+	}
+
+	if out, results, err := ProposeErrorReturnFix(src, fix); err != nil {
+		return nil, err
+	} else if len(results) > 0 {
+		add(out, 1.0, results[0])
+	}
+
+	if out, results, err := ProposeMissingMethodFix(src, fix); err != nil {
+		return nil, err
+	} else if len(results) > 0 {
+		add(out, 1.0, results[0])
+	}
+
+	if corrected, confidence, ok := ResolveUnexportedFix(fix, dir); ok {
+		f, err := astedit.Parse(fix.Diagnostic.File, src)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.Apply(astedit.ReplaceCallExpr{Pkg: fix.Package, From: fix.Symbol, To: corrected}); err != nil {
+			return nil, err
+		}
+		out, err := f.Format()
+		if err != nil {
+			return nil, err
+		}
+		bs, be, as, ae := patch.DiffLines(src, out)
+		add(out, confidence, patch.Result{
+			File: fix.Diagnostic.File, Kind: patch.KindReplaceCall, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	return candidates, nil
+}
+
+// Rank sorts candidates by Confidence, highest first; ties prefer gofix
+// candidates over LLM ones since a deterministic fix that's equally
+// confident is cheaper and more reviewable than one from a model call.
+func Rank(candidates []Candidate) []Candidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Source == "gofix" && candidates[j].Source != "gofix"
+	})
+	return candidates
+}
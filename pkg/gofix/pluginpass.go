@@ -0,0 +1,98 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// RunPluginPass vets each package containing a path in paths and applies
+// every Fixer in reg whose Applies reports true for a diagnostic, the same
+// transactional per-package way RunUnusedPass applies the built-in
+// unused-import/var fixes. It's the entry point .repopatcher.yaml's
+// `fixers:` plugins run through.
+func RunPluginPass(paths []string, reg *Registry) ([]patch.Result, error) {
+	dirs := map[string]bool{}
+	for _, p := range paths {
+		dirs[filepath.Dir(p)] = true
+	}
+
+	var results []patch.Result
+	for dir := range dirs {
+		cmd := exec.Command("go", "vet", "./...")
+		cmd.Dir = dir
+		out, _ := cmd.CombinedOutput() // a failing vet is expected; that's the diagnostics we want
+
+		diags := ParseDiagnostics(string(out))
+		files := map[string]bool{}
+		for _, d := range diags {
+			if len(reg.For(d)) > 0 {
+				files[filepath.Join(dir, filepath.Base(d.File))] = true
+			}
+		}
+		txnFiles := make([]string, 0, len(files))
+		for f := range files {
+			txnFiles = append(txnFiles, f)
+		}
+		txn, err := patch.Begin(txnFiles)
+		if err != nil {
+			return results, err
+		}
+
+		var dirResults []patch.Result
+		for _, d := range diags {
+			fixers := reg.For(d)
+			if len(fixers) == 0 {
+				continue
+			}
+			file := filepath.Join(dir, filepath.Base(d.File))
+			before := txn.Read(file)
+
+			fset := token.NewFileSet()
+			fileAST, err := parser.ParseFile(fset, d.File, before, parser.ParseComments)
+			if err != nil {
+				_ = txn.Rollback()
+				return results, fmt.Errorf("gofix: %w", err)
+			}
+
+			var changed bool
+			for _, f := range fixers {
+				c, err := f.Apply(fset, fileAST, d)
+				if err != nil {
+					_ = txn.Rollback()
+					return results, err
+				}
+				changed = changed || c
+			}
+			if !changed {
+				continue
+			}
+
+			var buf bytes.Buffer
+			if err := format.Node(&buf, fset, fileAST); err != nil {
+				_ = txn.Rollback()
+				return results, fmt.Errorf("gofix: %w", err)
+			}
+			patched := buf.Bytes()
+
+			bs, be, as, ae := patch.DiffLines(before, patched)
+			txn.Write(file, patched)
+			dirResults = append(dirResults, patch.Result{
+				File: d.File, Kind: patch.KindPluginFix, Changed: true,
+				BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+			})
+		}
+
+		if err := txn.Commit(); err != nil {
+			return results, fmt.Errorf("gofix: %w", err)
+		}
+		results = append(results, dirResults...)
+	}
+	return results, nil
+}
@@ -0,0 +1,70 @@
+package gofix
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// PrintfFixer is the built-in Fixer for CategoryPrintfMismatch: go vet's
+// printf analyzer reporting a format verb that doesn't match the type of
+// the argument passed for it. It rewrites the verb in place to the one
+// verbForType picks for the argument's actual type (fix.TargetType).
+type PrintfFixer struct{}
+
+func init() {
+	DefaultRegistry.Register("printf-mismatch", PrintfFixer{})
+}
+
+func (PrintfFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryPrintfMismatch
+}
+
+// Apply finds the format-string literal on diag's line that contains the
+// offending verb and replaces just that occurrence with the verb
+// verbForType picks for fix.TargetType. It reports no change if no string
+// literal on that line contains the verb, or verbForType doesn't know the
+// argument's type.
+func (PrintfFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fix := Classify(diag)
+	newVerb, ok := verbForType(fix.TargetType)
+	if !ok {
+		return false, nil
+	}
+
+	var changed bool
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING || changed {
+			return !changed
+		}
+		if fset.Position(lit.Pos()).Line != diag.Line || !strings.Contains(lit.Value, fix.Verb) {
+			return true
+		}
+		lit.Value = strings.Replace(lit.Value, fix.Verb, newVerb, 1)
+		changed = true
+		return false
+	})
+	return changed, nil
+}
+
+// verbForType returns the fmt verb that formats a value of goType without
+// a type mismatch, for the handful of types go vet's printf analyzer most
+// commonly flags. %v is deliberately not offered as a blanket fallback:
+// returning false for an unrecognized type leaves the diagnostic for
+// review rather than silently picking a verb that might itself be wrong.
+func verbForType(goType string) (verb string, ok bool) {
+	switch goType {
+	case "string":
+		return "%s", true
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		return "%d", true
+	case "float32", "float64":
+		return "%f", true
+	case "bool":
+		return "%t", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,160 @@
+package gofix
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ScoredCandidate is one import-path candidate for a missing-import Fix,
+// paired with the score Ranker.RankedCandidates gave it.
+type ScoredCandidate struct {
+	Path  string
+	Score float64
+}
+
+// Score weights candidate as an import path to add to fileAST, favoring, in
+// order of how much each term contributes:
+//   - fileAST already importing candidate by exact path - the strongest
+//     possible signal,
+//   - how often pkg's other files already import candidate, the module's
+//     established choice,
+//   - standard-library packages over third-party ones exporting the same
+//     symbol name,
+//   - an import whose path layout (single- vs multi-segment) matches what
+//     fileAST already imports, a weak signal of the file's existing style,
+//   - shorter import paths, as a tie-breaker among otherwise-equal
+//     candidates.
+//
+// fileAST and pkg may both be nil, in which case their terms are skipped.
+func Score(candidate string, fileAST *ast.File, pkg *packages.Package) float64 {
+	var score float64
+
+	if isStdlibPath(candidate) {
+		score += 3.0
+	}
+
+	if fileAST != nil {
+		switch {
+		case alreadyImports(fileAST, candidate):
+			score += 5.0
+		case sameImportStyle(fileAST, candidate):
+			score += 2.0
+		}
+	}
+
+	if pkg != nil {
+		score += float64(usageCount(pkg, candidate)) * 5.0
+	}
+
+	score -= float64(len(candidate)) * 0.01
+	return score
+}
+
+// isStdlibPath reports whether path looks like a standard-library import:
+// its first path segment has no dot, the convention every module path
+// (which must start with a domain) relies on.
+func isStdlibPath(path string) bool {
+	first := path
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		first = path[:i]
+	}
+	return !strings.Contains(first, ".")
+}
+
+// alreadyImports reports whether fileAST already has an import of
+// candidate - the strongest possible signal that it's the right one,
+// stronger even than a file importing some other package with the same
+// path layout.
+func alreadyImports(fileAST *ast.File, candidate string) bool {
+	for _, imp := range fileAST.Imports {
+		if strings.Trim(imp.Path.Value, `"`) == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// sameImportStyle reports whether fileAST already imports a package whose
+// path is single-segment vs multi-segment in the same way candidate is.
+func sameImportStyle(fileAST *ast.File, candidate string) bool {
+	multiSegment := strings.Contains(candidate, "/")
+	for _, imp := range fileAST.Imports {
+		p := strings.Trim(imp.Path.Value, `"`)
+		if strings.Contains(p, "/") == multiSegment {
+			return true
+		}
+	}
+	return false
+}
+
+// usageCount counts how many of pkg's already-parsed files import
+// candidate.
+func usageCount(pkg *packages.Package, candidate string) int {
+	n := 0
+	for _, f := range pkg.Syntax {
+		for _, imp := range f.Imports {
+			if strings.Trim(imp.Path.Value, `"`) == candidate {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Ranker scores a missing-import Fix's candidate import paths against one
+// file's existing style and (optionally) one package's usage frequency, so
+// the missing-import fixer can pick the generally-better dependency instead
+// of whichever candidate Index happened to list first.
+type Ranker struct {
+	Index *SymbolIndex
+	File  *ast.File
+	Pkg   *packages.Package
+}
+
+// RankedCandidates returns up to k import paths that export symbol - from
+// the stdlib table, the module's dependency graph (if Index has a
+// resolver), and symbol itself as a last-resort guess - sorted by Score,
+// highest first. Ties break alphabetically so results are deterministic.
+func (r Ranker) RankedCandidates(symbol string, k int) []ScoredCandidate {
+	seen := map[string]bool{}
+	var scored []ScoredCandidate
+	add := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		scored = append(scored, ScoredCandidate{Path: path, Score: Score(path, r.File, r.Pkg)})
+	}
+
+	for _, path := range r.Index.bySymbol[symbol] {
+		add(path)
+	}
+	if r.Index.resolver != nil {
+		if candidates, err := r.Index.resolver.Candidates(symbol); err == nil {
+			for _, path := range candidates {
+				add(path)
+			}
+		}
+	}
+	if len(scored) == 0 {
+		// Nothing in the stdlib table or the module's dependency graph
+		// exports symbol; fall back to treating the identifier itself as
+		// the import path, the overwhelmingly common case for stdlib
+		// packages whose import path is just their package name.
+		add(symbol)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Path < scored[j].Path
+	})
+	if k > 0 && len(scored) > k {
+		scored = scored[:k]
+	}
+	return scored
+}
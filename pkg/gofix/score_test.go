@@ -0,0 +1,94 @@
+package gofix
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// fakeResolver is a ModuleResolver stub for tests that don't want to shell
+// out to `go list -m`.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) Candidates(symbol string) ([]string, error) {
+	return r[symbol], nil
+}
+
+// TestRankedCandidatesOrdering covers the symbol/context pairs Score's
+// weighting terms are meant to separate: stdlib preference, matching the
+// file's existing import style, module-level usage frequency, and the
+// path-length tie-breaker.
+func TestRankedCandidatesOrdering(t *testing.T) {
+	parse := func(t *testing.T, src string) *ast.File {
+		t.Helper()
+		f, err := parser.ParseFile(token.NewFileSet(), "x.go", src, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return f
+	}
+
+	idx := &SymbolIndex{
+		bySymbol: map[string][]string{
+			"New":      {"errors", "log", "log/slog"},
+			"Sprintf":  {"fmt"},
+			"Wrap":     {}, // left empty; resolved only via the fake resolver below
+			"ParseInt": {"strconv"},
+		},
+		resolver: fakeResolver{
+			"Wrap": {"github.com/fake/errs"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		symbol string
+		src    string // file whose existing imports should steer the score
+		want   string // expected top candidate
+	}{
+		{
+			name:   "prefers an already-imported package over other candidates",
+			symbol: "New",
+			src:    "package p\n\nimport \"errors\"\n",
+			want:   "errors",
+		},
+		{
+			name:   "prefers a multi-segment package when the file already imports one",
+			symbol: "New",
+			src:    "package p\n\nimport \"log/slog\"\n",
+			want:   "log/slog",
+		},
+		{
+			name:   "falls back to the shortest stdlib candidate with no steer from the file",
+			symbol: "New",
+			src:    "package p\n",
+			want:   "log", // shortest of errors/log/log/slog once nothing else distinguishes them
+		},
+		{
+			name:   "single known candidate wins regardless of file contents",
+			symbol: "Sprintf",
+			src:    "package p\n\nimport \"strconv\"\n",
+			want:   "fmt",
+		},
+		{
+			name:   "resolves a third-party symbol via the module resolver",
+			symbol: "Wrap",
+			src:    "package p\n",
+			want:   "github.com/fake/errs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileAST := parse(t, tt.src)
+			ranked := Ranker{Index: idx, File: fileAST}.RankedCandidates(tt.symbol, 1)
+			if len(ranked) == 0 {
+				t.Fatalf("RankedCandidates(%q) returned nothing", tt.symbol)
+			}
+			if got := ranked[0].Path; got != tt.want {
+				t.Errorf("RankedCandidates(%q)[0].Path = %q, want %q", tt.symbol, got, tt.want)
+			}
+		})
+	}
+}
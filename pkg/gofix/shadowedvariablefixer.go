@@ -0,0 +1,156 @@
+package gofix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// ShadowedVariableFixer is a second built-in Fixer for
+// CategoryShadowedVariable, complementing ShadowFixer. ShadowFixer merges a
+// single-name `x := expr` into the outer x by turning := into =, but that
+// only works when every name on the shadowing line already exists in the
+// outer scope - it deliberately leaves a multi-name `val, err := f()`
+// alone, since declaring err alone would still need := as long as val is
+// new. For that case the only fix is to rename the shadowing declaration
+// and every reference to it within its scope, which is what this Fixer
+// does. It's a no-op on every diagnostic ShadowFixer already resolves, so
+// the two run safely alongside each other in the same Registry.
+type ShadowedVariableFixer struct{}
+
+func init() {
+	DefaultRegistry.Register("shadowed-variable-rename", ShadowedVariableFixer{})
+}
+
+func (ShadowedVariableFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryShadowedVariable
+}
+
+func (ShadowedVariableFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fix := Classify(diag)
+
+	assign, block, idx := findShadowingAssign(fset, file, fix.Symbol, diag.Line)
+	if assign == nil || len(assign.Lhs) < 2 {
+		// A single-name `:=` is ShadowFixer's job.
+		return false, nil
+	}
+
+	newName := nonConflictingName(file, fix.Symbol)
+	renameInScope(block.List[idx:], fix.Symbol, newName)
+	return true, nil
+}
+
+// findShadowingAssign locates the `:=` statement at line that declares
+// name, along with the *ast.BlockStmt it's a direct member of and its
+// index there. Every statement belongs to exactly one block's List, so the
+// first (and only) block found containing it is its tightest enclosing
+// scope.
+func findShadowingAssign(fset *token.FileSet, file *ast.File, name string, line int) (assign *ast.AssignStmt, block *ast.BlockStmt, idx int) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		b, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i, stmt := range b.List {
+			a, ok := stmt.(*ast.AssignStmt)
+			if !ok || a.Tok != token.DEFINE || fset.Position(a.Pos()).Line != line {
+				continue
+			}
+			for _, lhs := range a.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Name == name {
+					assign, block, idx = a, b, i
+				}
+			}
+		}
+		return true
+	})
+	return assign, block, idx
+}
+
+// renameInScope renames oldName to newName in its own declaring statement
+// (stmts[0], the `:=` that introduced it) and every reference after it in
+// the same scope.
+func renameInScope(stmts []ast.Stmt, oldName, newName string) {
+	if len(stmts) == 0 {
+		return
+	}
+	if assign, ok := stmts[0].(*ast.AssignStmt); ok {
+		for _, lhs := range assign.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok && id.Name == oldName {
+				id.Name = newName
+			}
+		}
+	}
+	for _, stmt := range stmts[1:] {
+		renameReferences(stmt, oldName, newName)
+	}
+}
+
+// renameReferences renames every Ident named oldName under n, except inside
+// a nested block that redeclares oldName itself - that block's oldName
+// refers to its own, independently shadowed variable, not the one being
+// renamed here, so it (and everything under it) is left untouched.
+func renameReferences(n ast.Node, oldName, newName string) {
+	ast.Inspect(n, func(node ast.Node) bool {
+		if block, ok := node.(*ast.BlockStmt); ok && blockRedeclares(block, oldName) {
+			return false
+		}
+		if id, ok := node.(*ast.Ident); ok && id.Name == oldName {
+			id.Name = newName
+		}
+		return true
+	})
+}
+
+// blockRedeclares reports whether b directly declares name via `:=` or
+// `var`, i.e. whether entering b means name refers to a new variable.
+func blockRedeclares(b *ast.BlockStmt, name string) bool {
+	for _, stmt := range b.List {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE {
+				continue
+			}
+			for _, lhs := range s.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Name == name {
+					return true
+				}
+			}
+		case *ast.DeclStmt:
+			gd, ok := s.Decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for _, id := range vs.Names {
+					if id.Name == name {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// nonConflictingName returns base suffixed with the smallest positive
+// integer that isn't already an identifier anywhere in file.
+func nonConflictingName(file *ast.File, base string) string {
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s%d", base, i)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
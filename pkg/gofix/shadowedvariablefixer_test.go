@@ -0,0 +1,164 @@
+package gofix_test
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+// TestShadowedVariableFixerIgnoresSingleName confirms the new Fixer leaves
+// a single-name `x := expr` shadow alone - that's ShadowFixer's job - so
+// the two coexist in DefaultRegistry without fighting over the same
+// diagnostic.
+func TestShadowedVariableFixerIgnoresSingleName(t *testing.T) {
+	const src = `package main
+
+func f() int {
+	x := 1
+	{
+		x := 2
+		_ = x
+	}
+	return x
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "f.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	diag := gofix.Diagnostic{File: "f.go", Line: 6, Message: `declaration of "x" shadows declaration at f.go:4:2`}
+	changed, err := (gofix.ShadowedVariableFixer{}).Apply(fset, file, diag)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if changed {
+		t.Error("Apply reported a change for a single-name shadow, want it left for ShadowFixer")
+	}
+}
+
+// TestShadowedVariableFixerRenamesMultiNameShadow covers the case
+// ShadowFixer explicitly can't: a multi-name `val, err := f()` where err
+// shadows an outer err. The fix renames the inner err (and its references)
+// rather than merging it into the outer one, since val still needs :=.
+func TestShadowedVariableFixerRenamesMultiNameShadow(t *testing.T) {
+	const src = `package main
+
+import "strconv"
+
+func run() error {
+	err := firstStep()
+	if err != nil {
+		return err
+	}
+	val, err := strconv.Atoi("5")
+	if err != nil {
+		return err
+	}
+	return useVal(val)
+}
+
+func firstStep() error            { return nil }
+func useVal(v int) error          { return nil }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "run.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	diag := gofix.Diagnostic{File: "run.go", Line: 10, Message: `declaration of "err" shadows declaration at run.go:6:2`}
+	changed, err := (gofix.ShadowedVariableFixer{}).Apply(fset, file, diag)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("Apply reported no change for a multi-name shadow")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	got := buf.String()
+
+	if !strings.Contains(got, "val, err1 := strconv.Atoi") {
+		t.Errorf("got:\n%s\nwant the shadowing err renamed to err1", got)
+	}
+	if !strings.Contains(got, "if err1 != nil {\n\t\treturn err1\n\t}\n\treturn useVal(val)") {
+		t.Errorf("got:\n%s\nwant every reference to the inner err renamed, and the outer err/return left alone", got)
+	}
+	if !strings.Contains(got, "err := firstStep()") || !strings.Contains(got, "if err != nil {\n\t\treturn err\n\t}\n\tval") {
+		t.Errorf("got:\n%s\nwant the outer err declaration and its own reference untouched", got)
+	}
+}
+
+// TestShadowedVariableFixerSkipsNestedReshadow covers a three-level
+// shadowing chain: err is shadowed once by a multi-name `:=` (which this
+// Fixer renames), and that renamed err is itself shadowed again inside a
+// nested block. References inside the nested block must keep referring to
+// the nested block's own err, not the renamed one.
+func TestShadowedVariableFixerSkipsNestedReshadow(t *testing.T) {
+	const src = `package main
+
+func run() error {
+	err := step1()
+	if err != nil {
+		return err
+	}
+	val, err := step2()
+	if err != nil {
+		return err
+	}
+	if val > 0 {
+		err := step3()
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func step1() error    { return nil }
+func step2() (int, error) { return 0, nil }
+func step3() error    { return nil }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "run.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	diag := gofix.Diagnostic{File: "run.go", Line: 8, Message: `declaration of "err" shadows declaration at run.go:4:2`}
+	changed, err := (gofix.ShadowedVariableFixer{}).Apply(fset, file, diag)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("Apply reported no change")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	got := buf.String()
+
+	if !strings.Contains(got, "val, err1 := step2()") {
+		t.Errorf("got:\n%s\nwant the middle err renamed to err1", got)
+	}
+	if !strings.Contains(got, "err := step3()") {
+		t.Errorf("got:\n%s\nwant the innermost, independently-shadowed err left untouched", got)
+	}
+	if !strings.Contains(got, "if err1 != nil {\n\t\treturn err1\n\t}") {
+		t.Errorf("got:\n%s\nwant err1's own if-block to reference err1", got)
+	}
+	if !strings.Contains(got, "return err1\n}\n\nfunc step1") {
+		t.Errorf("got:\n%s\nwant the final return (still in err1's scope, once the nested reshadow's block ends) to reference err1", got)
+	}
+	if !strings.Contains(got, "if err != nil {\n\t\t\treturn err\n\t\t}") {
+		t.Errorf("got:\n%s\nwant step3's own independently-shadowed err left untouched inside its nested block", got)
+	}
+}
@@ -0,0 +1,48 @@
+package gofix
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// ShadowFixer is the built-in Fixer for CategoryShadowedVariable: go vet's
+// shadow analyzer reporting that a `:=` redeclares a name already
+// declared in an outer scope. The only case it fixes is a single-name
+// `x := expr`, where "shadows declaration at ..." already guarantees an
+// outer x exists, so turning := into = drops the redundant inner
+// declaration and reuses the outer variable instead. A multi-name `:=`
+// (e.g. `val, err := f()`) is left alone: at least one of those names is
+// usually new, and Go requires := rather than = whenever any name on the
+// left is.
+type ShadowFixer struct{}
+
+func init() {
+	DefaultRegistry.Register("shadowed-variable", ShadowFixer{})
+}
+
+func (ShadowFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryShadowedVariable
+}
+
+func (ShadowFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fix := Classify(diag)
+
+	var changed bool
+	ast.Inspect(file, func(n ast.Node) bool {
+		if changed {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || ident.Name != fix.Symbol || fset.Position(assign.Pos()).Line != diag.Line {
+			return true
+		}
+		assign.Tok = token.ASSIGN
+		changed = true
+		return false
+	})
+	return changed, nil
+}
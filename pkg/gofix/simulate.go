@@ -0,0 +1,48 @@
+package gofix
+
+import "github.com/FernandoTN/repo-patcher/pkg/patch"
+
+// Simulate applies gofix's deterministic fixers to src in memory for every
+// diag in diags and returns the patched bytes, without touching the
+// filesystem: no go.mod is read or written (ProposeMissingImportFix always
+// runs with FixGoMod off) and import resolution is limited to the
+// built-in stdlib table (NewStaticSymbolIndex), since resolving against a
+// module's own dependency graph would mean shelling out to `go list`
+// against a real directory on disk. Callers that need the fuller,
+// module-aware pipeline should use Propose/RunUnusedPass/RunPluginPass
+// directly against a real file tree instead.
+//
+// diags are applied in order against the progressively patched source; a
+// diag that no fixer recognizes, or whose fixer reports no change, is
+// silently skipped rather than treated as an error, the same way Propose's
+// callers already do for a losing or inapplicable category.
+func Simulate(filename string, src []byte, diags []Diagnostic) ([]byte, error) {
+	out, _, err := SimulateWithResults(filename, src, diags)
+	return out, err
+}
+
+// SimulateWithResults is Simulate plus the patch.Result each applied fix
+// produced, in application order, for a caller that needs to report what
+// changed and not just the patched bytes (e.g. the HTTP server's
+// POST /patch handler).
+func SimulateWithResults(filename string, src []byte, diags []Diagnostic) ([]byte, []patch.Result, error) {
+	idx := NewStaticSymbolIndex()
+	out := src
+	var results []patch.Result
+	for _, d := range diags {
+		d.File = filename
+		fix := Classify(d)
+
+		candidates, err := Propose(out, fix, idx, "", UnusedRemove, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		ranked := Rank(candidates)
+		if len(ranked) == 0 {
+			continue
+		}
+		out = ranked[0].Patch
+		results = append(results, ranked[0].Result)
+	}
+	return out, results, nil
+}
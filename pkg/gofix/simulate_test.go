@@ -0,0 +1,95 @@
+package gofix_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+func TestSimulateAppliesMissingImportInMemory(t *testing.T) {
+	const src = `package main
+
+func Greet() string {
+	return fmt.Sprintf("hi")
+}
+`
+	diags := []gofix.Diagnostic{{Line: 4, Col: 9, Message: "undefined: fmt"}}
+
+	out, err := gofix.Simulate("greet.go", []byte(src), diags)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if !strings.Contains(string(out), `import "fmt"`) {
+		t.Errorf("Simulate didn't add the missing import:\n%s", out)
+	}
+}
+
+func TestSimulateSkipsUnrecognizedDiagnostics(t *testing.T) {
+	const src = `package main
+
+func Greet() string {
+	return "hi"
+}
+`
+	diags := []gofix.Diagnostic{{Line: 3, Message: "some diagnostic gofix has no fixer for"}}
+
+	out, err := gofix.Simulate("greet.go", []byte(src), diags)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if string(out) != src {
+		t.Errorf("Simulate changed source for an unrecognized diagnostic:\n%s", out)
+	}
+}
+
+func TestSimulateDoesNotWriteGoMod(t *testing.T) {
+	// Regression guard: Simulate is called with dir="" and FixGoMod=false
+	// internally, so a third-party missing import must never attempt a
+	// go.mod write. If it did, this would fail by trying to open a path
+	// under the empty string and erroring out instead of quietly adding
+	// the import.
+	const src = `package main
+
+func Do() {
+	errors.New("boom")
+}
+`
+	diags := []gofix.Diagnostic{{Line: 4, Message: "undefined: errors"}}
+
+	out, err := gofix.Simulate("do.go", []byte(src), diags)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if !strings.Contains(string(out), `import "errors"`) {
+		t.Errorf("Simulate didn't add the stdlib import:\n%s", out)
+	}
+}
+
+// FuzzSimulate feeds arbitrary byte strings as "Go source" to Simulate
+// alongside a fixed, representative diagnostic set, and checks it never
+// panics and never produces invalid UTF-8 when it succeeds. Most inputs
+// won't parse as Go at all; Simulate returning a parse error for those is
+// fine and expected - only a panic or non-UTF-8 success is a failure.
+func FuzzSimulate(f *testing.F) {
+	f.Add("package main\n\nfunc F() { fmt.Println(\"hi\") }\n")
+	f.Add("package main\n")
+	f.Add("not even close to go source")
+	f.Add("")
+
+	diags := []gofix.Diagnostic{
+		{Line: 1, Message: "undefined: fmt"},
+		{Line: 1, Message: `"strings" imported and not used`},
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		out, err := gofix.Simulate("fuzz.go", []byte(src), diags)
+		if err != nil {
+			return
+		}
+		if !utf8.Valid(out) {
+			t.Errorf("Simulate produced invalid UTF-8 for input %q: %q", src, out)
+		}
+	})
+}
@@ -0,0 +1,270 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// SSRRule is a structural search-and-replace rule: every call matching
+// Pattern is rewritten to Replacement, rebinding each "$name" placeholder
+// in Replacement to the argument expression it matched in Pattern. It's
+// aimed at API migrations (a function renamed, or its arguments
+// reordered) rather than compiler diagnostics - see RunSSRPass. Config
+// loads these from a config file's ssr_rules stanza (see Config.SSRRules).
+type SSRRule struct {
+	// Pattern is a call shape to match, e.g. "ioutil.ReadFile($a)". Every
+	// argument must be a "$name" placeholder; this is deliberately a
+	// simplified subset of golang.org/x/tools/refactor/eg's pattern
+	// language, not a full implementation of it.
+	Pattern string `yaml:"pattern"`
+	// Replacement is the call shape to rewrite matches to, e.g.
+	// "os.ReadFile($a)". It may reference Pattern's placeholders in any
+	// order, which is what makes an argument-reordering migration
+	// possible.
+	Replacement string `yaml:"replacement"`
+	// PatternImport is the import path providing Pattern's package. It's
+	// required so a local identifier that happens to share the package's
+	// name (e.g. a variable called ioutil) is never mistaken for it, and
+	// so the import can be removed once every match in a file has been
+	// rewritten away from it.
+	PatternImport string `yaml:"pattern_import"`
+	// ReplacementImport is the import path providing Replacement's
+	// package. It's added to the file if not already present.
+	ReplacementImport string `yaml:"replacement_import"`
+}
+
+// ssrCall is a parsed Pattern or Replacement: a package-qualified call
+// naming its arguments.
+type ssrCall struct {
+	Pkg  string
+	Func string
+	Args []string
+}
+
+var ssrCallRE = regexp.MustCompile(`^(\w+)\.(\w+)\(([^)]*)\)$`)
+
+// parseSSRCall parses s ("pkg.Func($a, $b)") into an ssrCall. Every
+// argument must be a "$name" placeholder.
+func parseSSRCall(s string) (ssrCall, error) {
+	m := ssrCallRE.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return ssrCall{}, fmt.Errorf("gofix: invalid SSR call shape %q, want \"pkg.Func($a, $b)\"", s)
+	}
+	var args []string
+	if argList := strings.TrimSpace(m[3]); argList != "" {
+		for _, a := range strings.Split(argList, ",") {
+			a = strings.TrimSpace(a)
+			if !strings.HasPrefix(a, "$") {
+				return ssrCall{}, fmt.Errorf("gofix: invalid SSR call shape %q: argument %q must be a $name placeholder", s, a)
+			}
+			args = append(args, strings.TrimPrefix(a, "$"))
+		}
+	}
+	return ssrCall{Pkg: m[1], Func: m[2], Args: args}, nil
+}
+
+// SSRFixer rewrites every call in a file matching one of Rules, regardless
+// of any compiler diagnostic - an API migration like ioutil.ReadFile ->
+// os.ReadFile is something this package wants to apply unconditionally,
+// not only when it happens to break the build. Like InitOrderFixer and
+// BuildConstraintFixer, it therefore isn't wired through the
+// Fixer/Registry diagnostic pipeline; RunSSRPass drives it directly over
+// every file in scope.
+type SSRFixer struct {
+	Rules []SSRRule
+}
+
+// Apply rewrites every call in file matching one of fx.Rules and reports
+// whether it changed anything. It's the caller's responsibility to decide
+// which imports are still referenced elsewhere in the file before
+// committing: Apply adds ReplacementImport when it performs at least one
+// substitution and removes PatternImport once none of its rule's matches
+// remain, using astutil so an import still needed by unrelated code (or by
+// another rule that targets the same package) is left alone.
+func (fx SSRFixer) Apply(fset *token.FileSet, file *ast.File) (bool, error) {
+	changed := false
+	for _, rule := range fx.Rules {
+		pattern, err := parseSSRCall(rule.Pattern)
+		if err != nil {
+			return changed, err
+		}
+		replacement, err := parseSSRCall(rule.Replacement)
+		if err != nil {
+			return changed, err
+		}
+		if len(pattern.Args) != len(replacement.Args) {
+			return changed, fmt.Errorf("gofix: SSR rule %q -> %q: pattern and replacement must bind the same placeholders", rule.Pattern, rule.Replacement)
+		}
+		argIndex := map[string]int{}
+		for i, name := range pattern.Args {
+			argIndex[name] = i
+		}
+
+		matched := false
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkgIdent, ok := sel.X.(*ast.Ident)
+			if !ok || pkgIdent.Name != pattern.Pkg || sel.Sel.Name != pattern.Func || len(call.Args) != len(pattern.Args) {
+				return true
+			}
+			if rule.PatternImport != "" && !importedAs(file, rule.PatternImport, pattern.Pkg) {
+				return true
+			}
+
+			newArgs := make([]ast.Expr, len(replacement.Args))
+			for i, name := range replacement.Args {
+				idx, ok := argIndex[name]
+				if !ok {
+					return true
+				}
+				newArgs[i] = call.Args[idx]
+			}
+			sel.X = ast.NewIdent(replacement.Pkg)
+			sel.Sel = ast.NewIdent(replacement.Func)
+			call.Args = newArgs
+			matched = true
+			return true
+		})
+		if !matched {
+			continue
+		}
+		changed = true
+
+		if rule.ReplacementImport != "" {
+			astutil.AddImport(fset, file, rule.ReplacementImport)
+		}
+		if rule.PatternImport != "" && !referencesIdent(file, pattern.Pkg) {
+			astutil.DeleteImport(fset, file, rule.PatternImport)
+		}
+	}
+	return changed, nil
+}
+
+// importedAs reports whether file imports path under the local name ident
+// - either because it's aliased to ident, or because ident is the
+// package's default name (the import's base path element, which is a
+// reasonable approximation without fully resolving the package - callers
+// needing exactness should set ReplacementImport/PatternImport to match
+// their SSRRule's Pkg names).
+func importedAs(file *ast.File, path, ident string) bool {
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		if importPath != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name == ident
+		}
+		return filepath.Base(importPath) == ident
+	}
+	return false
+}
+
+// referencesIdent reports whether file contains any remaining reference to
+// ident as a package selector (pkg.Something), used to decide whether
+// removing pattern's now-possibly-unused import is safe.
+func referencesIdent(file *ast.File, ident string) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == ident {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// RunSSRPass runs every rule in rules over each file in paths, writing
+// back any file it changes. It's structured the same transactional way as
+// RunUnusedPass/RunPluginPass: every file in a run is staged in a single
+// patch.Transaction and only committed once the whole pass succeeds.
+func RunSSRPass(paths []string, rules []SSRRule) ([]patch.Result, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	files := map[string]bool{}
+	for _, p := range paths {
+		files[p] = true
+	}
+	txnFiles := make([]string, 0, len(files))
+	for f := range files {
+		txnFiles = append(txnFiles, f)
+	}
+	txn, err := patch.Begin(txnFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []patch.Result
+	fixer := SSRFixer{Rules: rules}
+	for file := range files {
+		before := txn.Read(file)
+
+		fset := token.NewFileSet()
+		fileAST, err := parser.ParseFile(fset, file, before, parser.ParseComments)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+
+		changed, err := fixer.Apply(fset, fileAST)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+
+		if err := FormatImports(fset, fileAST, modulePathOf(filepath.Dir(file))); err != nil {
+			_ = txn.Rollback()
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, fileAST); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+
+		bs, be, as, ae := patch.DiffLines(before, patched)
+		txn.Write(file, patched)
+		results = append(results, patch.Result{
+			File: file, Kind: patch.KindSSR, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,177 @@
+package gofix
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSSRFixerMigratesIoutilReadFileToOsReadFile(t *testing.T) {
+	const src = `package p
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+func run() {
+	b, err := ioutil.ReadFile("x.txt")
+	if err != nil {
+		fmt.Println(err)
+	}
+	_ = b
+}
+`
+	rule := SSRRule{
+		Pattern:           "ioutil.ReadFile($path)",
+		Replacement:       "os.ReadFile($path)",
+		PatternImport:     "io/ioutil",
+		ReplacementImport: "os",
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	changed, err := (SSRFixer{Rules: []SSRRule{rule}}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, `os.ReadFile("x.txt")`) {
+		t.Errorf("got:\n%s\nwant a call to os.ReadFile", out)
+	}
+	if strings.Contains(out, "ioutil.ReadFile") {
+		t.Errorf("got:\n%s\nwant no remaining call to ioutil.ReadFile", out)
+	}
+	if strings.Contains(out, `"io/ioutil"`) {
+		t.Errorf("got:\n%s\nwant the io/ioutil import removed", out)
+	}
+	if !strings.Contains(out, `"os"`) {
+		t.Errorf("got:\n%s\nwant the os import added", out)
+	}
+}
+
+func TestSSRFixerLeavesIoutilImportWhenStillUsedElsewhere(t *testing.T) {
+	const src = `package p
+
+import (
+	"io/ioutil"
+)
+
+func run() {
+	_, _ = ioutil.ReadFile("x.txt")
+	_ = ioutil.Discard
+}
+`
+	rule := SSRRule{
+		Pattern:           "ioutil.ReadFile($path)",
+		Replacement:       "os.ReadFile($path)",
+		PatternImport:     "io/ioutil",
+		ReplacementImport: "os",
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if _, err := (SSRFixer{Rules: []SSRRule{rule}}).Apply(fset, file); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"io/ioutil"`) {
+		t.Errorf("got:\n%s\nwant the io/ioutil import kept since ioutil.Discard is still used", out)
+	}
+}
+
+func TestSSRFixerReordersArguments(t *testing.T) {
+	const src = `package p
+
+func run() {
+	pkg.OldFunc(1, 2)
+}
+`
+	rule := SSRRule{
+		Pattern:     "pkg.OldFunc($a, $b)",
+		Replacement: "pkg.NewFunc($b, $a)",
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	changed, err := (SSRFixer{Rules: []SSRRule{rule}}).Apply(fset, file)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatal("reported no change, want one")
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		t.Fatalf("format.Node: %v", err)
+	}
+	if got, want := buf.String(), "pkg.NewFunc(2, 1)"; !strings.Contains(got, want) {
+		t.Errorf("got:\n%s\nwant a call to %s", got, want)
+	}
+}
+
+func TestRunSSRPassWritesChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "p.go")
+	const src = `package p
+
+import "io/ioutil"
+
+func run() {
+	_, _ = ioutil.ReadFile("x.txt")
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	results, err := RunSSRPass([]string{path}, []SSRRule{{
+		Pattern:           "ioutil.ReadFile($path)",
+		Replacement:       "os.ReadFile($path)",
+		PatternImport:     "io/ioutil",
+		ReplacementImport: "os",
+	}})
+	if err != nil {
+		t.Fatalf("RunSSRPass: %v", err)
+	}
+	if len(results) != 1 || !results[0].Changed {
+		t.Fatalf("got %+v, want one Changed result", results)
+	}
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(out), "os.ReadFile") {
+		t.Errorf("got:\n%s\nwant the file rewritten on disk", out)
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+import "fmt"
+
+func main() {
+	msg := "unused"
+	fmt.Println("hi")
+}
@@ -0,0 +1,10 @@
+package main
+
+// Sum adds up xs.
+func Sum(xs []int) int {
+	total := 0
+	for _, v := range xs {
+		total += v
+	}
+	return total
+}
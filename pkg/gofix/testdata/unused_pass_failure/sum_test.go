@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+// The range variable i is declared but never used in the loop body - a
+// "declared and not used" diagnostic that ProposeUnusedFix can't resolve,
+// since astedit.InsertBlankUse only knows how to silence a := or var
+// declaration, not a for-range clause.
+func TestSum(t *testing.T) {
+	xs := []int{1, 2, 3}
+	for i := range xs {
+		_ = xs[0]
+	}
+
+	result := Sum(xs)
+	expected := 6
+
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
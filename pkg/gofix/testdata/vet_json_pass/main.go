@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+func report(n string) {
+	fmt.Printf("count: %d\n", n)
+}
+
+func describe() string {
+	err := first()
+	if err != nil {
+		err := second()
+		if err != nil {
+			return "failed twice"
+		}
+	}
+	return "ok"
+}
+
+func first() error  { return nil }
+func second() error { return nil }
+
+func main() {
+	report("3")
+	fmt.Println(describe())
+}
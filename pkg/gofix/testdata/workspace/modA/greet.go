@@ -0,0 +1,10 @@
+// Package greet is used by modB's scenario to prove WorkspaceResolver finds
+// a symbol across module boundaries rather than just within one module's
+// own dependency graph.
+package greet
+
+// Hello returns a greeting. Its only purpose in this fixture is to exist
+// in a package that a sibling module can import by package name alone.
+func Hello() string {
+	return "hello"
+}
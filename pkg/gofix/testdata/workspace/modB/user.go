@@ -0,0 +1,9 @@
+// Package user calls into modA's package without importing it - the
+// workspace-test.go fixture for WorkspaceResolver: a missing-import fix for
+// this file can't be satisfied by modB's own go.mod dependency graph, only
+// by searching the other modules go.work lists alongside it.
+package user
+
+func Greet() string {
+	return greet.Hello()
+}
@@ -0,0 +1,298 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// TestStubFixer finds a source file's exported, top-level functions that
+// no _test.go file in the same directory already references, and
+// generates a table-driven test stub for each: a tests slice of
+// {name, input..., want...} struct literals (every field but name left at
+// its zero value - the stub is a scaffold to fill in, not a working
+// assertion) and a `for _, tc := range tests` loop that calls the
+// function and compares its result(s) against tc.want with
+// reflect.DeepEqual.
+//
+// It only ever writes a brand-new <base>_test.go beside <base>.go: if
+// that file already exists, merging machine-generated stubs into whatever
+// a human already wrote there is a judgment call TestStubFixer leaves
+// alone rather than guessing where they belong.
+type TestStubFixer struct{}
+
+// stubFunc is one exported top-level function TestStubFixer found with no
+// existing test reference, along with the printed type text (via
+// format.Node, so generics and qualified types round-trip correctly) of
+// each of its parameters and results.
+type stubFunc struct {
+	name    string
+	params  []string
+	results []string
+}
+
+// newStubFunc extracts fn's parameter/result type texts using fset.
+func newStubFunc(fset *token.FileSet, fn *ast.FuncDecl) (stubFunc, error) {
+	sf := stubFunc{name: fn.Name.Name}
+	var err error
+	if fn.Type.Params != nil {
+		if sf.params, err = fieldTypeTexts(fset, fn.Type.Params.List); err != nil {
+			return stubFunc{}, err
+		}
+	}
+	if fn.Type.Results != nil {
+		if sf.results, err = fieldTypeTexts(fset, fn.Type.Results.List); err != nil {
+			return stubFunc{}, err
+		}
+	}
+	return sf, nil
+}
+
+// fieldTypeTexts flattens fields (each of which may declare several names
+// under one type, e.g. "a, b int") into one type-text entry per declared
+// value, so a stub struct field can be generated per parameter/result
+// individually even when the signature grouped them. An unnamed
+// parameter or result still contributes exactly one entry.
+func fieldTypeTexts(fset *token.FileSet, fields []*ast.Field) ([]string, error) {
+	var texts []string
+	for _, f := range fields {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, f.Type); err != nil {
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		typeText := buf.String()
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			texts = append(texts, typeText)
+		}
+	}
+	return texts, nil
+}
+
+// hasVariadicParam reports whether fn declares a variadic parameter
+// (func(nums ...int)). Its type is an *ast.Ellipsis, which format.Node
+// prints as "...int" - valid in a parameter list but not as a struct
+// field type, so a stub can't be generated for a function signature like
+// this the way fieldTypeTexts renders every other parameter.
+func hasVariadicParam(fn *ast.FuncDecl) bool {
+	if fn.Type.Params == nil {
+		return false
+	}
+	for _, f := range fn.Type.Params.List {
+		if _, ok := f.Type.(*ast.Ellipsis); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// testedNames collects every identifier referenced anywhere in dir's
+// existing _test.go files - a coarse but cheap proxy for "this function
+// already has a test", since a function a test exercises is necessarily
+// named somewhere in that test's source.
+func testedNames(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	names := map[string]bool{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				names[id.Name] = true
+			}
+			return true
+		})
+	}
+	return names, nil
+}
+
+// fieldNames returns the n stub struct field names for a prefix ("input"
+// or "want"): the bare prefix for a single value, prefix0/prefix1/... for
+// several, nil for none.
+func fieldNames(prefix string, n int) []string {
+	if n == 0 {
+		return nil
+	}
+	if n == 1 {
+		return []string{prefix}
+	}
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return names
+}
+
+// testFuncText renders fn's table-driven test stub as plain text.
+func testFuncText(fn stubFunc) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "func Test%s(t *testing.T) {\n", fn.name)
+	b.WriteString("tests := []struct {\nname string\n")
+
+	inputNames := fieldNames("input", len(fn.params))
+	for i, typ := range fn.params {
+		fmt.Fprintf(&b, "%s %s\n", inputNames[i], typ)
+	}
+	wantNames := fieldNames("want", len(fn.results))
+	for i, typ := range fn.results {
+		fmt.Fprintf(&b, "%s %s\n", wantNames[i], typ)
+	}
+	b.WriteString("}{\n{name: \"case 1\"},\n}\n\n")
+
+	b.WriteString("for _, tc := range tests {\n")
+	b.WriteString("t.Run(tc.name, func(t *testing.T) {\n")
+
+	args := make([]string, len(inputNames))
+	for i, n := range inputNames {
+		args[i] = "tc." + n
+	}
+	call := fmt.Sprintf("%s(%s)", fn.name, strings.Join(args, ", "))
+
+	switch len(wantNames) {
+	case 0:
+		fmt.Fprintf(&b, "%s\n", call)
+	case 1:
+		fmt.Fprintf(&b, "got := %s\n", call)
+		fmt.Fprintf(&b, "if !reflect.DeepEqual(got, tc.%s) {\n", wantNames[0])
+		fmt.Fprintf(&b, "t.Errorf(\"got %%v, want %%v\", got, tc.%s)\n", wantNames[0])
+		b.WriteString("}\n")
+	default:
+		gotNames := fieldNames("got", len(wantNames))
+		fmt.Fprintf(&b, "%s := %s\n", strings.Join(gotNames, ", "), call)
+		for i, g := range gotNames {
+			fmt.Fprintf(&b, "if !reflect.DeepEqual(%s, tc.%s) {\n", g, wantNames[i])
+			fmt.Fprintf(&b, "t.Errorf(\"got %%v, want %%v\", %s, tc.%s)\n", g, wantNames[i])
+			b.WriteString("}\n")
+		}
+	}
+
+	b.WriteString("})\n")
+	b.WriteString("}\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// generate renders the full <base>_test.go source for pkgName's funcs.
+func (TestStubFixer) generate(pkgName string, funcs []stubFunc) ([]byte, error) {
+	needsReflect := false
+	var body strings.Builder
+	for _, fn := range funcs {
+		if len(fn.results) > 0 {
+			needsReflect = true
+		}
+		body.WriteString(testFuncText(fn))
+		body.WriteString("\n")
+	}
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+	src.WriteString("import (\n")
+	if needsReflect {
+		src.WriteString("\"reflect\"\n")
+	}
+	src.WriteString("\"testing\"\n")
+	src.WriteString(")\n\n")
+	src.WriteString(body.String())
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, fmt.Errorf("gofix: format generated test stub: %w", err)
+	}
+	return formatted, nil
+}
+
+// RunTestStubPass runs TestStubFixer over each non-test source file in
+// paths that has no sibling <base>_test.go yet, writing one beside it
+// when that file declares at least one exported, top-level function with
+// no existing test reference in its directory. A file that already has a
+// <base>_test.go is left untouched, whether or not every one of its
+// functions is actually covered.
+func RunTestStubPass(paths []string) ([]patch.Result, error) {
+	var results []patch.Result
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		testPath := strings.TrimSuffix(path, ".go") + "_test.go"
+		if _, err := os.Stat(testPath); err == nil {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+
+		tested, err := testedNames(filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+
+		var funcs []stubFunc
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !ast.IsExported(fn.Name.Name) || tested[fn.Name.Name] {
+				continue
+			}
+			if hasVariadicParam(fn) {
+				// Skip just this function, not the whole file: a
+				// generated struct field can't declare a variadic
+				// type, and letting that error propagate out of
+				// generate would abort every other stub in paths too.
+				continue
+			}
+			sf, err := newStubFunc(fset, fn)
+			if err != nil {
+				return nil, err
+			}
+			funcs = append(funcs, sf)
+		}
+		if len(funcs) == 0 {
+			continue
+		}
+
+		source, err := (TestStubFixer{}).generate(file.Name.Name, funcs)
+		if err != nil {
+			return nil, err
+		}
+
+		txn, err := patch.Begin([]string{testPath})
+		if err != nil {
+			return nil, err
+		}
+		txn.Write(testPath, source)
+		if err := txn.Commit(); err != nil {
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		results = append(results, patch.Result{
+			File: testPath, Kind: patch.KindTestStub, Changed: true,
+			AfterStart: 1, AfterEnd: bytes.Count(source, []byte("\n")) + 1,
+		})
+	}
+	return results, nil
+}
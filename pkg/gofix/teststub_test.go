@@ -0,0 +1,137 @@
+package gofix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestRunTestStubPassGeneratesStubsForUntestedExportedFuncs(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "utils.go", `package p
+
+func FormatMessage(name string, age int) string { return "hi" }
+
+func GetGreeting(name string) string { return "hi " + name }
+`)
+
+	results, err := RunTestStubPass([]string{path})
+	if err != nil {
+		t.Fatalf("RunTestStubPass: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1: %+v", len(results), results)
+	}
+	if results[0].Kind != patch.KindTestStub {
+		t.Errorf("Kind = %v, want %v", results[0].Kind, patch.KindTestStub)
+	}
+
+	testPath := filepath.Join(dir, "utils_test.go")
+	out, err := os.ReadFile(testPath)
+	if err != nil {
+		t.Fatalf("read generated stub: %v", err)
+	}
+	outStr := string(out)
+	for _, want := range []string{
+		"func TestFormatMessage(t *testing.T) {",
+		"input0 string",
+		"input1 int",
+		"func TestGetGreeting(t *testing.T) {",
+		"reflect.DeepEqual",
+	} {
+		if !strings.Contains(outStr, want) {
+			t.Errorf("generated stub missing %q:\n%s", want, outStr)
+		}
+	}
+}
+
+func TestRunTestStubPassSkipsFileWithExistingTestFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "utils.go", `package p
+
+func FormatMessage(name string) string { return name }
+`)
+	writeFile(t, dir, "utils_test.go", `package p
+
+func TestSomethingElse(t *testing.T) {}
+`)
+
+	results, err := RunTestStubPass([]string{path})
+	if err != nil {
+		t.Fatalf("RunTestStubPass: %v", err)
+	}
+	if results != nil {
+		t.Errorf("got %v, want nil: utils_test.go already exists", results)
+	}
+}
+
+func TestRunTestStubPassSkipsVariadicFuncButStubsOthersInTheFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "utils.go", `package p
+
+func Sum(nums ...int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func GetGreeting(name string) string { return "hi " + name }
+`)
+
+	results, err := RunTestStubPass([]string{path})
+	if err != nil {
+		t.Fatalf("RunTestStubPass: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1: %+v", len(results), results)
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "utils_test.go"))
+	if err != nil {
+		t.Fatalf("read generated stub: %v", err)
+	}
+	if strings.Contains(string(out), "Sum") {
+		t.Errorf("stub covers Sum, which has a variadic parameter:\n%s", out)
+	}
+	if !strings.Contains(string(out), "func TestGetGreeting(t *testing.T) {") {
+		t.Errorf("stub missing GetGreeting, which has no variadic parameter:\n%s", out)
+	}
+}
+
+func TestRunTestStubPassSkipsFuncsAlreadyReferencedInATestFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "utils.go", `package p
+
+func FormatMessage(name string) string { return name }
+
+func GetGreeting(name string) string { return "hi " + name }
+`)
+	writeFile(t, dir, "other_test.go", `package p
+
+func TestFormatMessage(t *testing.T) { _ = FormatMessage("x") }
+`)
+
+	results, err := RunTestStubPass([]string{path})
+	if err != nil {
+		t.Fatalf("RunTestStubPass: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1: %+v", len(results), results)
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "utils_test.go"))
+	if err != nil {
+		t.Fatalf("read generated stub: %v", err)
+	}
+	if strings.Contains(string(out), "FormatMessage") {
+		t.Errorf("stub still covers FormatMessage, which other_test.go already references:\n%s", out)
+	}
+	if !strings.Contains(string(out), "GetGreeting") {
+		t.Errorf("stub missing GetGreeting, which has no existing test reference:\n%s", out)
+	}
+}
@@ -0,0 +1,134 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// TypeMismatchFixer is the built-in Fixer for CategoryTypeMismatch: the
+// compiler's "cannot use x (variable of type T) as U value in assignment"
+// error. Rather than running go/types itself - this package has no
+// existing go/types integration, and the compiler's own diagnostic already
+// names both types precisely - it parses SourceType/TargetType straight
+// out of that message (see typeMismatchRE) and wraps the assignment's
+// right-hand side in whatever conversion gets from one to the other.
+type TypeMismatchFixer struct{}
+
+func init() {
+	DefaultRegistry.Register("type-mismatch", TypeMismatchFixer{})
+}
+
+func (TypeMismatchFixer) Applies(diag Diagnostic) bool {
+	return Classify(diag).Category == CategoryTypeMismatch
+}
+
+// Apply finds the assignment statement diag points at and rewrites its
+// right-hand side with the conversion conversionFor picks for
+// fix.SourceType/TargetType, adding whatever import that conversion needs.
+// It reports no change if conversionFor doesn't know the pairing.
+func (TypeMismatchFixer) Apply(fset *token.FileSet, file *ast.File, diag Diagnostic) (bool, error) {
+	fix := Classify(diag)
+	convert, importPath, ok := conversionFor(fix.SourceType, fix.TargetType)
+	if !ok {
+		return false, nil
+	}
+
+	var target *ast.AssignStmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		if target != nil {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		ident, ok := assign.Rhs[0].(*ast.Ident)
+		if !ok || ident.Name != fix.Symbol || fset.Position(assign.Pos()).Line != diag.Line {
+			return true
+		}
+		target = assign
+		return false
+	})
+	if target == nil {
+		return false, fmt.Errorf("gofix: no assignment to %s found on line %d", fix.Symbol, diag.Line)
+	}
+
+	target.Rhs[0] = convert(target.Rhs[0])
+	if importPath != "" {
+		astutil.AddImport(fset, file, importPath)
+	}
+	return true, nil
+}
+
+// conversionFor returns the expression wrapper and import path (empty for
+// a builtin conversion) needed to turn a sourceType value into targetType,
+// and false if this fixer doesn't know that pairing. Only conversions to
+// string are supported today, matching the compiler diagnostics this
+// package actually sees in the wild.
+func conversionFor(sourceType, targetType string) (convert func(ast.Expr) ast.Expr, importPath string, ok bool) {
+	if targetType != "string" {
+		return nil, "", false
+	}
+	switch sourceType {
+	case "int":
+		return func(x ast.Expr) ast.Expr {
+			return &ast.CallExpr{Fun: &ast.SelectorExpr{X: ast.NewIdent("strconv"), Sel: ast.NewIdent("Itoa")}, Args: []ast.Expr{x}}
+		}, "strconv", true
+	case "[]byte":
+		return func(x ast.Expr) ast.Expr {
+			return &ast.CallExpr{Fun: ast.NewIdent("string"), Args: []ast.Expr{x}}
+		}, "", true
+	case "float64":
+		return func(x ast.Expr) ast.Expr {
+			return &ast.CallExpr{Fun: &ast.SelectorExpr{X: ast.NewIdent("fmt"), Sel: ast.NewIdent("Sprint")}, Args: []ast.Expr{x}}
+		}, "fmt", true
+	default:
+		return nil, "", false
+	}
+}
+
+// ProposeTypeMismatchFix resolves a CategoryTypeMismatch Fix through
+// TypeMismatchFixer and returns the file's source with the conversion
+// applied, plus the patch.Result describing the edit. It returns a nil
+// results slice if fix isn't a type-mismatch fix or the Fixer reports no
+// change (an unsupported source/target pairing).
+func ProposeTypeMismatchFix(src []byte, fix Fix) ([]byte, []patch.Result, error) {
+	if fix.Category != CategoryTypeMismatch {
+		return nil, nil, nil
+	}
+
+	fset := token.NewFileSet()
+	fileAST, err := parser.ParseFile(fset, fix.Diagnostic.File, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	changed, err := (TypeMismatchFixer{}).Apply(fset, fileAST, fix.Diagnostic)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !changed {
+		return nil, nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, fileAST); err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+	out := buf.Bytes()
+
+	bs, be, as, ae := patch.DiffLines(src, out)
+	result := patch.Result{
+		File: fix.Diagnostic.File, Kind: patch.KindReplaceCall, Changed: true,
+		BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+	}
+	return out, []patch.Result{result}, nil
+}
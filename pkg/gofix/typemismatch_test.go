@@ -0,0 +1,124 @@
+package gofix_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+// TestProposeTypeMismatchFix covers the three conversions TypeMismatchFixer
+// ships with: int, float64, and []byte all being assigned into a string.
+func TestProposeTypeMismatchFix(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		sourceType string
+		wantImport string // "" means no new import should appear
+		wantCall   string
+	}{
+		{
+			name: "int to string",
+			src: `package main
+
+func greet() string {
+	var s string
+	n := 5
+	s = n
+	return s
+}
+`,
+			sourceType: "int",
+			wantImport: `"strconv"`,
+			wantCall:   "strconv.Itoa(n)",
+		},
+		{
+			name: "float64 to string",
+			src: `package main
+
+func greet() string {
+	var s string
+	f := 5.5
+	s = f
+	return s
+}
+`,
+			sourceType: "float64",
+			wantImport: `"fmt"`,
+			wantCall:   "fmt.Sprint(f)",
+		},
+		{
+			name: "[]byte to string",
+			src: `package main
+
+func greet() string {
+	var s string
+	b := []byte("hi")
+	s = b
+	return s
+}
+`,
+			sourceType: "[]byte",
+			wantImport: "",
+			wantCall:   "string(b)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := gofix.Diagnostic{
+				File: "greet.go", Line: 6, Col: 2,
+				Message: "cannot use " + varNameFor(tt.src) + " (variable of type " + tt.sourceType + ") as string value in assignment",
+			}
+			fix := gofix.Classify(diag)
+			if fix.Category != gofix.CategoryTypeMismatch {
+				t.Fatalf("Classify category = %v, want %v", fix.Category, gofix.CategoryTypeMismatch)
+			}
+
+			out, results, err := gofix.ProposeTypeMismatchFix([]byte(tt.src), fix)
+			if err != nil {
+				t.Fatalf("ProposeTypeMismatchFix: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			if !results[0].Changed {
+				t.Error("Changed = false, want true")
+			}
+
+			got := string(out)
+			if !strings.Contains(got, tt.wantCall) {
+				t.Errorf("patched source missing %q:\n%s", tt.wantCall, got)
+			}
+			if tt.wantImport != "" && !strings.Contains(got, tt.wantImport) {
+				t.Errorf("patched source missing import %s:\n%s", tt.wantImport, got)
+			}
+		})
+	}
+}
+
+// varNameFor extracts the variable name assigned to s in the fixture
+// source, so the synthetic diagnostic's message matches what Classify
+// expects without hardcoding it separately per test case.
+func varNameFor(src string) string {
+	idx := strings.Index(src, "s = ")
+	rest := src[idx+len("s = "):]
+	return rest[:strings.IndexByte(rest, '\n')]
+}
+
+func TestConversionForUnsupportedPairingYieldsNoChange(t *testing.T) {
+	diag := gofix.Diagnostic{
+		File: "x.go", Line: 3, Col: 2,
+		Message: "cannot use n (variable of type int) as float64 value in assignment",
+	}
+	fix := gofix.Classify(diag)
+
+	src := "package main\n\nfunc f() {\n\tvar x float64\n\tn := 1\n\tx = n\n\t_ = x\n}\n"
+	_, results, err := gofix.ProposeTypeMismatchFix([]byte(src), fix)
+	if err != nil {
+		t.Fatalf("ProposeTypeMismatchFix: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d results for an unsupported pairing, want 0", len(results))
+	}
+}
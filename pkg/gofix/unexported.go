@@ -0,0 +1,139 @@
+package gofix
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// exactCaseInsensitiveConfidence is the Confidence assigned when the only
+// difference between the requested symbol and an exported one is casing
+// (e.g. "printf" vs "Printf"); these are applied automatically.
+const exactCaseInsensitiveConfidence = 1.0
+
+// maxProposeDistance is the largest Levenshtein distance, after lowercasing,
+// at which a candidate rename is still proposed (rather than ignored). Above
+// this the match is too weak to be worth surfacing.
+const maxProposeDistance = 2
+
+// ResolveUnexportedFix handles CategoryUndeclaredName and
+// CategoryUnexportedReference fixes caused by wrong casing, e.g.
+// `cannot refer to unexported name fmt.printf`. It loads fix.Package with
+// go/packages, looks for an exported identifier whose lowercase form is
+// close to fix.Symbol, and returns the corrected name plus a confidence:
+// an exact case-insensitive match auto-applies (confidence 1.0); anything
+// else within a Levenshtein distance of maxProposeDistance is returned as a
+// lower-confidence proposal for the patch-candidate pipeline to rank
+// against LLM suggestions. ok is false when nothing close enough is found.
+func ResolveUnexportedFix(fix Fix, dir string) (corrected string, confidence float64, ok bool) {
+	switch fix.Category {
+	case CategoryUnexportedReference, CategoryUndeclaredName:
+	default:
+		return "", 0, false
+	}
+	if fix.Package == "" || fix.Symbol == "" {
+		return "", 0, false
+	}
+
+	idents, err := exportedIdents(dir, fix.Package)
+	if err != nil || len(idents) == 0 {
+		return "", 0, false
+	}
+
+	want := strings.ToLower(fix.Symbol)
+	best, bestDist := "", -1
+	for _, ident := range idents {
+		if strings.ToLower(ident) == want {
+			return ident, exactCaseInsensitiveConfidence, true
+		}
+		d := levenshtein(want, strings.ToLower(ident))
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = ident, d
+		}
+	}
+	if bestDist >= 0 && bestDist <= maxProposeDistance {
+		// Confidence decays with edit distance: dist=1 -> 0.7, dist=2 -> 0.5.
+		return best, 0.9 - 0.2*float64(bestDist), true
+	}
+	return "", 0, false
+}
+
+// exportedIdents returns the exported top-level identifiers of importPath,
+// loaded from dir's module context.
+func exportedIdents(dir, importPath string) ([]string, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax, Dir: dir}
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return nil, fmt.Errorf("gofix: load package %s: %w", importPath, err)
+	}
+	var idents []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				idents = append(idents, exportedNamesOf(decl)...)
+			}
+		}
+	}
+	return idents, nil
+}
+
+func exportedNamesOf(decl ast.Decl) []string {
+	var names []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil && ast.IsExported(d.Name.Name) {
+			names = append(names, d.Name.Name)
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if ast.IsExported(n.Name) {
+						names = append(names, n.Name)
+					}
+				}
+			case *ast.TypeSpec:
+				if ast.IsExported(s.Name.Name) {
+					names = append(names, s.Name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
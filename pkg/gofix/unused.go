@@ -0,0 +1,353 @@
+package gofix
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/astedit"
+	"github.com/FernandoTN/repo-patcher/pkg/metrics"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+	"github.com/FernandoTN/repo-patcher/pkg/progress"
+)
+
+// UnusedMode controls how CategoryUnusedImport and CategoryUnusedVar fixes
+// are resolved. It is set from the patcher's --unused flag.
+type UnusedMode string
+
+const (
+	// UnusedRemove deletes the offending import or leaves the unused var
+	// for removal by the caller (vars can't be auto-deleted without
+	// rewriting the enclosing statement, so Remove mode blank-assigns them
+	// instead; see ProposeUnusedFix).
+	UnusedRemove UnusedMode = "remove"
+	// UnusedBlank keeps the symbol around as `_ "path"` or `_ = v`, for
+	// callers that want to preserve the intent behind a not-yet-finished
+	// import or variable.
+	UnusedBlank UnusedMode = "blank"
+	// UnusedAsk declines to auto-apply and signals the caller to surface
+	// the diagnostic for human or LLM review instead.
+	UnusedAsk UnusedMode = "ask"
+)
+
+// ParseUnusedMode parses the --unused flag value, defaulting to
+// UnusedRemove for an empty string.
+func ParseUnusedMode(s string) (UnusedMode, error) {
+	switch UnusedMode(s) {
+	case "", UnusedRemove:
+		return UnusedRemove, nil
+	case UnusedBlank:
+		return UnusedBlank, nil
+	case UnusedAsk:
+		return UnusedAsk, nil
+	default:
+		return "", fmt.Errorf("gofix: invalid --unused mode %q (want remove|blank|ask)", s)
+	}
+}
+
+// ProposeUnusedFix resolves a CategoryUnusedImport or CategoryUnusedVar fix
+// under mode, returning the patched source and the patch.Result describing
+// the edit. It returns a nil results slice when mode is UnusedAsk (the
+// diagnostic should be left for review) or fix isn't an unused-import/var
+// fix.
+func ProposeUnusedFix(src []byte, fix Fix, mode UnusedMode) ([]byte, []patch.Result, error) {
+	if fix.Category != CategoryUnusedImport && fix.Category != CategoryUnusedVar {
+		return nil, nil, nil
+	}
+	if mode == UnusedAsk {
+		return nil, nil, nil
+	}
+
+	f, err := astedit.Parse(fix.Diagnostic.File, src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	kind := patch.KindRemoveImport
+	switch fix.Category {
+	case CategoryUnusedImport:
+		if mode == UnusedBlank {
+			kind = patch.KindBlankImport
+			err = f.Apply(astedit.RemoveImport{Path: fix.Package}, astedit.AddImport{Path: fix.Package, Name: "_"})
+		} else {
+			err = f.Apply(astedit.RemoveImport{Path: fix.Package})
+		}
+	case CategoryUnusedVar:
+		// Go has no single-statement "delete this declaration and its
+		// uses" op that's always safe; blank-assigning is the only
+		// transformation that's correct regardless of what else in the
+		// function references the variable, so Remove mode falls back to
+		// it for vars too.
+		kind = patch.KindBlankUse
+		err = f.Apply(astedit.InsertBlankUse{Name: fix.Symbol})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	out, err := f.Format()
+	if err != nil {
+		return nil, nil, fmt.Errorf("gofix: %w", err)
+	}
+
+	bs, be, as, ae := patch.DiffLines(src, out)
+	result := patch.Result{
+		File: fix.Diagnostic.File, Kind: kind, Changed: true,
+		BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+	}
+	return out, []patch.Result{result}, nil
+}
+
+// RunOptions configures a RunUnusedPass invocation.
+type RunOptions struct {
+	// Mode controls how unused-import/var fixes are resolved.
+	Mode UnusedMode
+	// DryRun, when true, never writes fixed content to disk: each
+	// returned patch.Result's DiffOutput holds a unified diff of the
+	// change that would have been made instead.
+	DryRun bool
+	// CachePath, if non-empty, is a patch.PatchCache file RunUnusedPass
+	// loads before the pass and saves after it: a file whose contents
+	// exactly match what the cache last saw for it is patched straight
+	// from the cached result instead of being reparsed. Empty means no
+	// caching.
+	CachePath string
+	// Verify, when true, runs patch.Verify against each package directory
+	// after its transaction commits, and rolls the commit back if the
+	// package still fails to build - e.g. a diagnostic RunUnusedPass
+	// doesn't know how to fix was left behind alongside the one it did.
+	// Has no effect in DryRun, which never commits anything to verify.
+	Verify bool
+	// ProvenanceSuffix, if non-empty, is appended to each patched file's
+	// own path to name a JSON sidecar file (via patch.WriteProvenance)
+	// recording which rule changed it and when - e.g. ".provenance.json"
+	// turns "foo.go" into "foo.go.provenance.json". Empty disables
+	// provenance tracking. Has no effect in DryRun, which never commits
+	// anything to have provenance for.
+	ProvenanceSuffix string
+	// History, if non-nil, has Apply called once per patched file with its
+	// before/after content, so an interactive caller can later Undo or
+	// Redo individual fixes. Has no effect in DryRun, which never commits
+	// anything to undo.
+	History *patch.History
+	// Metrics, if non-nil, receives FileScanned/FixApplied/PatchError/
+	// ObserveDuration calls as the pass runs. A nil Metrics is a no-op, so
+	// callers that never configured one don't pay any cost.
+	Metrics *metrics.Metrics
+	// Progress, if non-nil, receives Start/FileStarted/FileDone/Done calls
+	// as the pass runs, so a caller can show a progress bar (see
+	// progress.TerminalReporter) instead of the tool appearing to hang on
+	// a large codebase. A nil Progress is a no-op.
+	Progress progress.ProgressReporter
+}
+
+// RunOption configures a RunOptions value built by NewRunOptions, for
+// callers that want to assemble one incrementally (e.g. only attaching a
+// ProgressReporter when a --progress flag asks for one) instead of writing
+// out the whole struct literal themselves.
+type RunOption func(*RunOptions)
+
+// WithProgress returns a RunOption that attaches r to a RunOptions value.
+func WithProgress(r progress.ProgressReporter) RunOption {
+	return func(o *RunOptions) { o.Progress = r }
+}
+
+// NewRunOptions builds a RunOptions by applying opts in order over the zero
+// value.
+func NewRunOptions(opts ...RunOption) RunOptions {
+	var o RunOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// fixerRuleVersion is the version recorded in every ProvenanceEntry this
+// pass writes. There's only ever been one revision of each built-in
+// fixer's behavior so far; this is the seam for bumping it once one
+// changes in a way worth distinguishing in an audit trail.
+const fixerRuleVersion = "1"
+
+// recordProvenance appends one ProvenanceEntry per result to
+// fileProvenance[file] and stamps the same entries onto each result's own
+// Provenance field, when opts.ProvenanceSuffix is set. It's a no-op
+// otherwise, so callers can call it unconditionally.
+func recordProvenance(fileProvenance map[string][]patch.ProvenanceEntry, opts RunOptions, file string, fix Fix, fixResults []patch.Result) {
+	if opts.ProvenanceSuffix == "" {
+		return
+	}
+	for i := range fixResults {
+		entry := patch.ProvenanceEntry{
+			Rule:        string(fix.Category),
+			RuleVersion: fixerRuleVersion,
+			AppliedAt:   time.Now(),
+			Hunk: patch.HunkRange{
+				BeforeStart: fixResults[i].BeforeStart,
+				BeforeEnd:   fixResults[i].BeforeEnd,
+				AfterStart:  fixResults[i].AfterStart,
+				AfterEnd:    fixResults[i].AfterEnd,
+			},
+		}
+		fixResults[i].Provenance = append(fixResults[i].Provenance, entry)
+		fileProvenance[file] = append(fileProvenance[file], entry)
+	}
+}
+
+// RunUnusedPass vets each package containing a path in paths, classifies
+// the resulting diagnostics, and rewrites any file with an auto-applicable
+// unused-import/unused-var fix under opts.Mode. It's the entry point the
+// repo-patcher CLI's --unused and --dry-run flags drive.
+//
+// This uses `go vet` rather than `go build` because unused-import/var
+// breakage is just as often in a _test.go file (see E004), and `go build
+// ./...` never compiles test files at all.
+//
+// Each dir's fixes are staged in a patch.Transaction and only committed
+// once every fix in that package has resolved cleanly: if proposing a fix
+// for one file fails partway through (e.g. astedit.InsertBlankUse can't
+// find a range-clause variable's declaration), the transaction is rolled
+// back so no file in the package is left half-patched on disk. In
+// opts.DryRun, the transaction is never committed at all.
+func RunUnusedPass(paths []string, opts RunOptions) ([]patch.Result, error) {
+	if opts.Progress != nil {
+		opts.Progress.Start(len(paths))
+		defer opts.Progress.Done()
+	}
+
+	dirs := map[string]bool{}
+	for _, p := range paths {
+		dirs[filepath.Dir(p)] = true
+	}
+
+	var cache *patch.PatchCache
+	if opts.CachePath != "" {
+		c, err := patch.LoadPatchCache(opts.CachePath)
+		if err != nil {
+			return nil, err
+		}
+		cache = c
+	}
+
+	var results []patch.Result
+	for dir := range dirs {
+		cmd := exec.Command("go", "vet", "./...")
+		cmd.Dir = dir
+		out, _ := cmd.CombinedOutput() // a failing vet is expected; that's the diagnostics we want
+
+		fixes := ClassifyAll(string(out))
+		files := map[string]bool{}
+		for _, fix := range fixes {
+			if fix.Category == CategoryUnusedImport || fix.Category == CategoryUnusedVar {
+				files[filepath.Join(dir, filepath.Base(fix.Diagnostic.File))] = true
+			}
+		}
+		txnFiles := make([]string, 0, len(files))
+		for f := range files {
+			txnFiles = append(txnFiles, f)
+		}
+		txn, err := patch.Begin(txnFiles)
+		if err != nil {
+			return results, err
+		}
+
+		var dirResults []patch.Result
+		fileProvenance := map[string][]patch.ProvenanceEntry{}
+		for _, fix := range fixes {
+			if fix.Category != CategoryUnusedImport && fix.Category != CategoryUnusedVar {
+				continue
+			}
+			file := filepath.Join(dir, filepath.Base(fix.Diagnostic.File))
+			before := txn.Read(file)
+			opts.Metrics.FileScanned()
+			if opts.Progress != nil {
+				opts.Progress.FileStarted(file)
+			}
+			start := time.Now()
+
+			if cache != nil {
+				if entry, ok := cache.Lookup(file, before); ok {
+					if opts.DryRun {
+						diffText := patch.UnifiedDiff(filepath.Base(fix.Diagnostic.File), before, entry.Patched)
+						for i := range entry.Results {
+							entry.Results[i].DiffOutput = diffText
+						}
+					} else {
+						txn.Write(file, entry.Patched)
+						recordProvenance(fileProvenance, opts, file, fix, entry.Results)
+						if opts.History != nil {
+							opts.History.Apply(patch.Result{File: file}, before, entry.Patched)
+						}
+						opts.Metrics.FixApplied(string(fix.Category))
+					}
+					opts.Metrics.ObserveDuration(time.Since(start))
+					if opts.Progress != nil && len(entry.Results) > 0 {
+						opts.Progress.FileDone(file, entry.Results[len(entry.Results)-1])
+					}
+					dirResults = append(dirResults, entry.Results...)
+					continue
+				}
+			}
+
+			patched, fixResults, err := ProposeUnusedFix(before, fix, opts.Mode)
+			if err != nil {
+				opts.Metrics.PatchError()
+				_ = txn.Rollback()
+				return results, err
+			}
+			if len(fixResults) == 0 {
+				continue
+			}
+			if cache != nil {
+				cache.Store(file, before, patch.CacheEntry{Results: fixResults, Patched: patched})
+			}
+			if opts.DryRun {
+				diffText := patch.UnifiedDiff(filepath.Base(fix.Diagnostic.File), before, patched)
+				for i := range fixResults {
+					fixResults[i].DiffOutput = diffText
+				}
+			} else {
+				txn.Write(file, patched)
+				recordProvenance(fileProvenance, opts, file, fix, fixResults)
+				if opts.History != nil {
+					opts.History.Apply(patch.Result{File: file}, before, patched)
+				}
+				opts.Metrics.FixApplied(string(fix.Category))
+			}
+			opts.Metrics.ObserveDuration(time.Since(start))
+			if opts.Progress != nil {
+				opts.Progress.FileDone(file, fixResults[len(fixResults)-1])
+			}
+			dirResults = append(dirResults, fixResults...)
+		}
+
+		if !opts.DryRun {
+			if err := txn.Commit(); err != nil {
+				opts.Metrics.PatchError()
+				return results, fmt.Errorf("gofix: %w", err)
+			}
+			if opts.Verify {
+				if err := patch.Verify(dir); err != nil {
+					opts.Metrics.PatchError()
+					_ = txn.Rollback()
+					return results, err
+				}
+			}
+			if opts.ProvenanceSuffix != "" {
+				for file, entries := range fileProvenance {
+					if err := patch.WriteProvenance(file+opts.ProvenanceSuffix, entries); err != nil {
+						return results, err
+					}
+				}
+			}
+		}
+		results = append(results, dirResults...)
+	}
+
+	if cache != nil {
+		if err := cache.Save(opts.CachePath); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,100 @@
+package gofix
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// RunVetJSONPass applies every Fixer in reg whose Applies reports true for
+// a Diagnostic src produces, the same transactional way RunPluginPass
+// applies fixes for diagnostics it gets from running `go vet` itself. It's
+// the entry point for driving the fixer registry off of diagnostics
+// produced elsewhere - e.g. a `go vet -json` report a CI job already has
+// lying around - instead of re-running analysis. Each Diagnostic's File is
+// resolved against dir unless it's already absolute.
+func RunVetJSONPass(dir string, src DiagnosticSource, reg *Registry) ([]patch.Result, error) {
+	diags, err := src.Diagnostics()
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]bool{}
+	for _, d := range diags {
+		if len(reg.For(d)) > 0 {
+			files[resolveDiagFile(dir, d.File)] = true
+		}
+	}
+	txnFiles := make([]string, 0, len(files))
+	for f := range files {
+		txnFiles = append(txnFiles, f)
+	}
+	txn, err := patch.Begin(txnFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []patch.Result
+	for _, d := range diags {
+		fixers := reg.For(d)
+		if len(fixers) == 0 {
+			continue
+		}
+		file := resolveDiagFile(dir, d.File)
+		before := txn.Read(file)
+
+		fset := token.NewFileSet()
+		fileAST, err := parser.ParseFile(fset, d.File, before, parser.ParseComments)
+		if err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+
+		var changed bool
+		for _, f := range fixers {
+			c, err := f.Apply(fset, fileAST, d)
+			if err != nil {
+				_ = txn.Rollback()
+				return nil, err
+			}
+			changed = changed || c
+		}
+		if !changed {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, fileAST); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("gofix: %w", err)
+		}
+		patched := buf.Bytes()
+
+		bs, be, as, ae := patch.DiffLines(before, patched)
+		txn.Write(file, patched)
+		results = append(results, patch.Result{
+			File: d.File, Kind: patch.KindPluginFix, Changed: true,
+			BeforeStart: bs, BeforeEnd: be, AfterStart: as, AfterEnd: ae,
+		})
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("gofix: %w", err)
+	}
+	return results, nil
+}
+
+// resolveDiagFile joins a Diagnostic's File with dir unless it's already
+// absolute, matching how go vet -json reports file paths relative to the
+// directory it was invoked from.
+func resolveDiagFile(dir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
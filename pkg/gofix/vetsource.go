@@ -0,0 +1,90 @@
+package gofix
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiagnosticSource produces the Diagnostics a Registry's Fixers act on,
+// decoupling that registry from how those diagnostics were found. The
+// built-in passes (RunUnusedPass, RunPluginPass) get theirs by invoking
+// `go vet`/`go build` themselves; GoVetSource instead reads a vet JSON
+// report that was already produced elsewhere in the build pipeline.
+type DiagnosticSource interface {
+	Diagnostics() ([]Diagnostic, error)
+}
+
+// GoVetSource reads the JSON `go vet -json ./...` emits (see `go doc
+// cmd/vet`) and converts every finding into a Diagnostic. Set Reader to
+// consume that JSON from a pipe or anything else already in memory;
+// leave it nil and set Path to read it from a file instead.
+type GoVetSource struct {
+	Reader io.Reader
+	Path   string
+}
+
+// vetReport is `go vet -json`'s schema: a map of package import path to a
+// map of analyzer name to the findings that analyzer reported.
+type vetReport map[string]map[string][]struct {
+	Posn    string `json:"posn"`
+	Message string `json:"message"`
+}
+
+// Diagnostics implements DiagnosticSource.
+func (s GoVetSource) Diagnostics() ([]Diagnostic, error) {
+	r := s.Reader
+	if r == nil {
+		f, err := os.Open(s.Path)
+		if err != nil {
+			return nil, fmt.Errorf("gofix: open vet JSON %s: %w", s.Path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var report vetReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("gofix: parse vet JSON: %w", err)
+	}
+
+	var diags []Diagnostic
+	for _, analyzers := range report {
+		for _, findings := range analyzers {
+			for _, finding := range findings {
+				file, line, col := parsePosn(finding.Posn)
+				diags = append(diags, Diagnostic{File: file, Line: line, Col: col, Message: finding.Message})
+			}
+		}
+	}
+	// Map iteration order is random; sort so two runs over the same JSON
+	// (and, e.g., golden-file tests) see Diagnostics in the same order.
+	sort.Slice(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Col < diags[j].Col
+	})
+	return diags, nil
+}
+
+// parsePosn splits a vet "posn" field (`file:line:col`) into its parts.
+// It splits from the right so a Windows-style drive letter in file (which
+// itself contains a colon) isn't mistaken for the line/col separator.
+func parsePosn(posn string) (file string, line, col int) {
+	parts := strings.Split(posn, ":")
+	if len(parts) < 3 {
+		return posn, 0, 0
+	}
+	col, _ = strconv.Atoi(parts[len(parts)-1])
+	line, _ = strconv.Atoi(parts[len(parts)-2])
+	file = strings.Join(parts[:len(parts)-2], ":")
+	return file, line, col
+}
@@ -0,0 +1,100 @@
+package gofix_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+// TestGoVetSourceParsesCannedJSON feeds GoVetSource a canned `go vet -json`
+// report (testdata/vet_json/sample.json) covering one printf finding and
+// one shadow finding, and checks both come back as Diagnostics Classify
+// recognizes.
+func TestGoVetSourceParsesCannedJSON(t *testing.T) {
+	src := gofix.GoVetSource{Path: filepath.Join("testdata", "vet_json", "sample.json")}
+	diags, err := src.Diagnostics()
+	if err != nil {
+		t.Fatalf("Diagnostics: %v", err)
+	}
+	if len(diags) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %+v", len(diags), diags)
+	}
+
+	// Sorted by file, then line: the printf finding (line 6) precedes the
+	// shadow finding (line 12).
+	printfDiag, shadowDiag := diags[0], diags[1]
+
+	printfFix := gofix.Classify(printfDiag)
+	if printfFix.Category != gofix.CategoryPrintfMismatch {
+		t.Errorf("printf diagnostic classified as %v, want %v", printfFix.Category, gofix.CategoryPrintfMismatch)
+	}
+	if printfFix.Verb != "%d" || printfFix.TargetType != "string" {
+		t.Errorf("printf Fix = %+v, want Verb=%%d TargetType=string", printfFix)
+	}
+
+	shadowFix := gofix.Classify(shadowDiag)
+	if shadowFix.Category != gofix.CategoryShadowedVariable {
+		t.Errorf("shadow diagnostic classified as %v, want %v", shadowFix.Category, gofix.CategoryShadowedVariable)
+	}
+	if shadowFix.Symbol != "err" {
+		t.Errorf("shadow Fix.Symbol = %q, want %q", shadowFix.Symbol, "err")
+	}
+}
+
+// TestGoVetSourceReadsFromReader exercises the piped-in path: Reader set
+// instead of Path.
+func TestGoVetSourceReadsFromReader(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "vet_json", "sample.json"))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+
+	src := gofix.GoVetSource{Reader: f}
+	diags, err := src.Diagnostics()
+	if err != nil {
+		t.Fatalf("Diagnostics: %v", err)
+	}
+	if len(diags) != 2 {
+		t.Fatalf("got %d diagnostics, want 2", len(diags))
+	}
+}
+
+// TestRunVetJSONPassAppliesPrintfAndShadowFixers drives RunVetJSONPass
+// over testdata/vet_json_pass using the same canned report, and checks
+// both built-in fixers actually landed: the printf verb corrected and the
+// inner shadowing `:=` turned into `=`.
+func TestRunVetJSONPassAppliesPrintfAndShadowFixers(t *testing.T) {
+	workDir := t.TempDir()
+	if err := copyDir(filepath.Join("testdata", "vet_json_pass"), workDir); err != nil {
+		t.Fatalf("copy testdata: %v", err)
+	}
+
+	src := gofix.GoVetSource{Path: filepath.Join("testdata", "vet_json", "sample.json")}
+	results, err := gofix.RunVetJSONPass(workDir, src, gofix.DefaultRegistry)
+	if err != nil {
+		t.Fatalf("RunVetJSONPass: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+
+	got, err := os.ReadFile(filepath.Join(workDir, "main.go"))
+	if err != nil {
+		t.Fatalf("read patched main.go: %v", err)
+	}
+	patched := string(got)
+
+	if !strings.Contains(patched, `fmt.Printf("count: %s\n", n)`) {
+		t.Errorf("printf verb wasn't corrected:\n%s", patched)
+	}
+	if !strings.Contains(patched, "err = second()") {
+		t.Errorf("shadowing := wasn't turned into =:\n%s", patched)
+	}
+	if strings.Contains(patched, "err := second()") {
+		t.Errorf("shadowing := is still present:\n%s", patched)
+	}
+}
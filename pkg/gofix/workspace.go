@@ -0,0 +1,105 @@
+package gofix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// WorkspaceLoader resolves a go.work file's `use` directives into the set
+// of module roots it lists, so the missing-import pipeline can search
+// sibling modules in the same workspace instead of being scoped to a
+// single module's own go.mod dependency graph.
+type WorkspaceLoader struct {
+	// WorkFile is the path to the go.work file to load.
+	WorkFile string
+}
+
+// ModuleRoots parses l.WorkFile and returns the absolute directory of
+// every module named in a `use` directive, in file order.
+func (l WorkspaceLoader) ModuleRoots() ([]string, error) {
+	data, err := os.ReadFile(l.WorkFile)
+	if err != nil {
+		return nil, fmt.Errorf("gofix: read %s: %w", l.WorkFile, err)
+	}
+	wf, err := modfile.ParseWork(l.WorkFile, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gofix: parse %s: %w", l.WorkFile, err)
+	}
+
+	workDir := filepath.Dir(l.WorkFile)
+	roots := make([]string, 0, len(wf.Use))
+	for _, u := range wf.Use {
+		roots = append(roots, filepath.Join(workDir, u.Path))
+	}
+	return roots, nil
+}
+
+// Load returns a packages.Config scoped to each module root ModuleRoots
+// finds. Each module gets its own Config rather than one combined one, so
+// loading a module with a currently-broken import doesn't also fail the
+// load of its otherwise-healthy siblings.
+func (l WorkspaceLoader) Load() ([]*packages.Config, error) {
+	roots, err := l.ModuleRoots()
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]*packages.Config, 0, len(roots))
+	for _, dir := range roots {
+		configs = append(configs, &packages.Config{
+			Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps,
+			Dir:  dir,
+			// Go auto-activates workspace mode here since dir sits under
+			// WorkFile's own directory, and workspace mode rejects
+			// -mod=mod GOFLAGS even though it's the default some
+			// environments set for non-workspace tooling's convenience.
+			Env: append(os.Environ(), "GOFLAGS=-mod=readonly"),
+		})
+	}
+	return configs, nil
+}
+
+// WorkspaceResolver is a ModuleResolver that searches every module in a
+// go.work workspace for a package named pkgName, the case GoListResolver
+// can't cover: a missing import satisfied by a sibling module in the same
+// workspace rather than an entry in the importing module's own go.mod.
+type WorkspaceResolver struct {
+	// Loader enumerates the workspace's module roots.
+	Loader WorkspaceLoader
+}
+
+// Candidates implements ModuleResolver.
+func (r WorkspaceResolver) Candidates(pkgName string) ([]string, error) {
+	configs, err := r.Loader.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	for _, cfg := range configs {
+		pkgs, err := packages.Load(cfg, "./...")
+		if err != nil {
+			return nil, fmt.Errorf("gofix: load workspace module %s: %w", cfg.Dir, err)
+		}
+		for _, p := range pkgs {
+			if p.Name == pkgName {
+				candidates = append(candidates, p.PkgPath)
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// BuildWorkspaceSymbolIndex is BuildSymbolIndex's workspace-aware
+// counterpart: it seeds the same std-library table, but resolves a
+// missing import's bare package identifier by searching every module
+// go.work lists (via WorkspaceResolver) instead of a single module's own
+// dependency graph.
+func BuildWorkspaceSymbolIndex(workFile string) *SymbolIndex {
+	idx := NewStaticSymbolIndex()
+	idx.resolver = WorkspaceResolver{Loader: WorkspaceLoader{WorkFile: workFile}}
+	return idx
+}
@@ -0,0 +1,78 @@
+package gofix_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+)
+
+// TestWorkspaceLoaderModuleRoots exercises testdata/workspace, a go.work
+// listing two modules, and checks ModuleRoots resolves both `use`
+// directives to absolute directories.
+func TestWorkspaceLoaderModuleRoots(t *testing.T) {
+	l := gofix.WorkspaceLoader{WorkFile: filepath.Join("testdata", "workspace", "go.work")}
+
+	roots, err := l.ModuleRoots()
+	if err != nil {
+		t.Fatalf("ModuleRoots: %v", err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("got %d roots, want 2: %v", len(roots), roots)
+	}
+	for i, want := range []string{"modA", "modB"} {
+		if filepath.Base(roots[i]) != want {
+			t.Errorf("roots[%d] = %s, want basename %s", i, roots[i], want)
+		}
+	}
+}
+
+// TestWorkspaceResolverFindsSiblingModulePackage exercises
+// testdata/workspace/modB/user.go, which calls into modA's "greet" package
+// without importing it: a missing-import fix for modB can only be
+// satisfied by searching modA, a sibling module in the same workspace, not
+// anything modB's own go.mod requires.
+func TestWorkspaceResolverFindsSiblingModulePackage(t *testing.T) {
+	r := gofix.WorkspaceResolver{Loader: gofix.WorkspaceLoader{WorkFile: filepath.Join("testdata", "workspace", "go.work")}}
+
+	candidates, err := r.Candidates("greet")
+	if err != nil {
+		t.Fatalf("Candidates: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0] != "example.com/moda" {
+		t.Fatalf("Candidates(\"greet\") = %v, want [example.com/moda]", candidates)
+	}
+}
+
+// TestBuildWorkspaceSymbolIndexFixesCrossModuleImport drives
+// ProposeMissingImportFix against modB/user.go with the index
+// BuildWorkspaceSymbolIndex returns, confirming the full missing-import
+// pipeline inserts "example.com/moda" - a sibling workspace module, not
+// anything modB's own (dependency-free) go.mod could have resolved.
+func TestBuildWorkspaceSymbolIndexFixesCrossModuleImport(t *testing.T) {
+	src := []byte(`package user
+
+func Greet() string {
+	return greet.Hello()
+}
+`)
+	fix := gofix.Fix{
+		Category:   gofix.CategoryMissingImport,
+		Package:    "greet",
+		Confidence: 1.0,
+		Diagnostic: gofix.Diagnostic{File: "user.go", Line: 4, Message: "undefined: greet"},
+	}
+
+	idx := gofix.BuildWorkspaceSymbolIndex(filepath.Join("testdata", "workspace", "go.work"))
+	out, results, err := gofix.ProposeMissingImportFix(src, fix, idx, gofix.ImportFixOptions{})
+	if err != nil {
+		t.Fatalf("ProposeMissingImportFix: %v", err)
+	}
+	if len(results) != 1 || !results[0].Changed {
+		t.Fatalf("expected one applied fix, got %+v", results)
+	}
+	if !strings.Contains(string(out), `"example.com/moda"`) {
+		t.Errorf("expected import of example.com/moda, got:\n%s", out)
+	}
+}
@@ -0,0 +1,321 @@
+// Package lspclient launches gopls over stdio and speaks just enough LSP to
+// read textDocument/publishDiagnostics notifications and request
+// textDocument/codeAction. When gopls already offers a quick fix (e.g. "Add
+// import \"fmt\"") the patcher can apply it directly, giving a zero-LLM path
+// that also covers source.organizeImports and gofmt formatting for free;
+// the LLM is only invoked when no code action resolves every diagnostic.
+package lspclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client is a connection to a single `gopls serve` process communicating
+// over stdin/stdout with JSON-RPC 2.0, Content-Length framed messages.
+type Client struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+
+	nextID int64
+
+	mu       sync.Mutex
+	pending  map[int64]chan rpcResponse
+	diagsMu  sync.Mutex
+	diags    map[string][]Diagnostic
+	closeErr error
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcNotification struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// Start launches `gopls serve` rooted at dir and performs the LSP
+// initialize/initialized handshake.
+func Start(ctx context.Context, dir string) (*Client, error) {
+	cmd := exec.CommandContext(ctx, "gopls", "serve")
+	cmd.Dir = dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("lspclient: stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("lspclient: stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("lspclient: start gopls: %w", err)
+	}
+
+	c, err := NewClient(dir, stdin, stdout)
+	if err != nil {
+		return nil, err
+	}
+	c.cmd = cmd
+	return c, nil
+}
+
+// NewClient performs the LSP initialize/initialized handshake over an
+// already-connected JSON-RPC 2.0 stdio pair rooted at dir, and returns once
+// gopls (or whatever is on the other end of stdout) is ready for
+// DidOpen/CodeActions calls. Start is the usual way to get a Client,
+// wrapping a real gopls subprocess's Stdin/StdoutPipe; NewClient exists so
+// tests can substitute a fake gopls speaking the same framing over a pipe,
+// without spawning a real subprocess.
+func NewClient(dir string, stdin io.WriteCloser, stdout io.Reader) (*Client, error) {
+	c := &Client{
+		stdin:   stdin,
+		stdout:  bufio.NewReader(stdout),
+		pending: map[int64]chan rpcResponse{},
+		diags:   map[string][]Diagnostic{},
+	}
+	go c.readLoop()
+
+	rootURI := pathToURI(dir)
+	if _, err := c.call("initialize", map[string]interface{}{
+		"processId": nil,
+		"rootUri":   rootURI,
+		"capabilities": map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"codeAction":  map[string]interface{}{},
+				"publishDiagnostics": map[string]interface{}{},
+			},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("lspclient: initialize: %w", err)
+	}
+	if err := c.notify("initialized", map[string]interface{}{}); err != nil {
+		return nil, fmt.Errorf("lspclient: initialized: %w", err)
+	}
+	return c, nil
+}
+
+// Close shuts down gopls cleanly. For a Client built with NewClient
+// directly (no subprocess of its own to wait on), it only closes stdin.
+func (c *Client) Close() error {
+	_, _ = c.call("shutdown", nil)
+	_ = c.notify("exit", nil)
+	_ = c.stdin.Close()
+	if c.cmd == nil {
+		return nil
+	}
+	return c.cmd.Wait()
+}
+
+// DidOpen tells gopls about a file's contents so it can compute diagnostics
+// for it.
+func (c *Client) DidOpen(path, contents string) error {
+	return c.notify("textDocument/didOpen", didOpenParams{
+		TextDocument: textDocumentItem{
+			URI:        pathToURI(path),
+			LanguageID: "go",
+			Version:    1,
+			Text:       contents,
+		},
+	})
+}
+
+// Diagnostics returns the most recently published diagnostics for path.
+// Diagnostics arrive asynchronously as notifications, so callers should
+// call DidOpen and then give gopls a moment (or poll) before reading this.
+func (c *Client) Diagnostics(path string) []Diagnostic {
+	c.diagsMu.Lock()
+	defer c.diagsMu.Unlock()
+	return append([]Diagnostic(nil), c.diags[pathToURI(path)]...)
+}
+
+// WaitForDiagnostics blocks until gopls has published diagnostics for path
+// at least once (even an empty set - a clean file still gets a
+// publishDiagnostics notification, just with no Diagnostics) or timeout
+// elapses, then returns whatever's there. Diagnostics publish
+// asynchronously after DidOpen, so most callers should go through this
+// rather than racing Diagnostics directly.
+func (c *Client) WaitForDiagnostics(path string, timeout time.Duration) []Diagnostic {
+	uri := pathToURI(path)
+	deadline := time.Now().Add(timeout)
+	for {
+		c.diagsMu.Lock()
+		d, published := c.diags[uri]
+		c.diagsMu.Unlock()
+		if published || time.Now().After(deadline) {
+			return append([]Diagnostic(nil), d...)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// CodeActions requests quick fixes for rng in path, scoped to diags.
+func (c *Client) CodeActions(path string, rng Range, diags []Diagnostic) ([]CodeAction, error) {
+	raw, err := c.call("textDocument/codeAction", codeActionParams{
+		TextDocument: textDocumentIdentifier{URI: pathToURI(path)},
+		Range:        rng,
+		Context:      codeActionContext{Diagnostics: diags},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var actions []CodeAction
+	if err := json.Unmarshal(raw, &actions); err != nil {
+		return nil, fmt.Errorf("lspclient: decode code actions: %w", err)
+	}
+	return actions, nil
+}
+
+// ApplyCodeAction applies action's WorkspaceEdit directly to the files on
+// disk, returning the new contents keyed by absolute path. It does not
+// re-save them; callers write the returned contents wherever the rest of
+// the patch pipeline expects patched files to land.
+func ApplyCodeAction(action CodeAction, current map[string]string) (map[string]string, error) {
+	out := map[string]string{}
+	for uri, edits := range action.Edit.Changes {
+		path, err := uriToPath(uri)
+		if err != nil {
+			return nil, err
+		}
+		src, ok := current[path]
+		if !ok {
+			return nil, fmt.Errorf("lspclient: no content loaded for %s", path)
+		}
+		out[path] = applyTextEdits(src, edits)
+	}
+	return out, nil
+}
+
+func (c *Client) readLoop() {
+	for {
+		msg, err := readMessage(c.stdout)
+		if err != nil {
+			return
+		}
+		var withID struct {
+			ID *int64 `json:"id"`
+		}
+		_ = json.Unmarshal(msg, &withID)
+
+		if withID.ID != nil {
+			var resp rpcResponse
+			if err := json.Unmarshal(msg, &resp); err != nil {
+				continue
+			}
+			c.mu.Lock()
+			ch := c.pending[resp.ID]
+			delete(c.pending, resp.ID)
+			c.mu.Unlock()
+			if ch != nil {
+				ch <- resp
+			}
+			continue
+		}
+
+		var note rpcNotification
+		if err := json.Unmarshal(msg, &note); err != nil {
+			continue
+		}
+		if note.Method == "textDocument/publishDiagnostics" {
+			var params PublishDiagnosticsParams
+			if err := json.Unmarshal(note.Params, &params); err == nil {
+				c.diagsMu.Lock()
+				c.diags[params.URI] = params.Diagnostics
+				c.diagsMu.Unlock()
+			}
+		}
+	}
+}
+
+func (c *Client) call(method string, params interface{}) (json.RawMessage, error) {
+	id := atomic.AddInt64(&c.nextID, 1)
+	ch := make(chan rpcResponse, 1)
+	c.mu.Lock()
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	if err := writeMessage(c.stdin, rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return nil, err
+	}
+	resp := <-ch
+	if resp.Error != nil {
+		return nil, fmt.Errorf("lspclient: %s: %s", method, resp.Error.Message)
+	}
+	return resp.Result, nil
+}
+
+func (c *Client) notify(method string, params interface{}) error {
+	return writeMessage(c.stdin, rpcRequest{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func writeMessage(w io.Writer, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(body))
+	buf.Write(body)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func readMessage(r *bufio.Reader) (json.RawMessage, error) {
+	var length int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == "\r\n" || line == "\n" {
+			break
+		}
+		fmt.Sscanf(line, "Content-Length: %d", &length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func pathToURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("lspclient: parse uri %s: %w", uri, err)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
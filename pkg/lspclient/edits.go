@@ -0,0 +1,71 @@
+package lspclient
+
+import "strings"
+
+// applyTextEdits applies edits to src and returns the result. Edits are
+// applied from the end of the document backwards so earlier offsets stay
+// valid as later ones are consumed, matching how LSP clients are expected
+// to apply a WorkspaceEdit.
+func applyTextEdits(src string, edits []TextEdit) string {
+	lines := splitKeepEnds(src)
+	sorted := append([]TextEdit(nil), edits...)
+	sortEditsDescending(sorted)
+
+	for _, e := range sorted {
+		start := offsetOf(lines, e.Range.Start)
+		end := offsetOf(lines, e.Range.End)
+		flat := strings.Join(lines, "")
+		flat = flat[:start] + e.NewText + flat[end:]
+		lines = splitKeepEnds(flat)
+	}
+	return strings.Join(lines, "")
+}
+
+func sortEditsDescending(edits []TextEdit) {
+	for i := 1; i < len(edits); i++ {
+		for j := i; j > 0 && less(edits[j-1].Range.Start, edits[j].Range.Start); j-- {
+			edits[j], edits[j-1] = edits[j-1], edits[j]
+		}
+	}
+}
+
+// less reports whether a comes strictly before b in document order.
+func less(a, b Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Character < b.Character
+}
+
+func splitKeepEnds(s string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			out = append(out, s[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
+func offsetOf(lines []string, pos Position) int {
+	offset := 0
+	for i := 0; i < pos.Line && i < len(lines); i++ {
+		offset += len(lines[i])
+	}
+	if pos.Line < len(lines) {
+		offset += min(pos.Character, len(lines[pos.Line]))
+	}
+	return offset
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
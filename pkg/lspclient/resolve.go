@@ -0,0 +1,53 @@
+package lspclient
+
+// Resolve drives the zero-LLM path for a single file: it asks gopls for
+// code actions covering every diagnostic already published for path, and
+// applies the first quick-fix-kind action per diagnostic. It returns the
+// patched contents (nil if no diagnostics needed fixing) and whether every
+// diagnostic was addressed; callers should only fall back to the LLM when
+// resolved is false.
+func (c *Client) Resolve(path, contents string) (patched string, resolved bool, err error) {
+	diags := c.Diagnostics(path)
+	if len(diags) == 0 {
+		return contents, true, nil
+	}
+
+	current := map[string]string{path: contents}
+	remaining := map[int]bool{}
+	for i := range diags {
+		remaining[i] = true
+	}
+
+	for i, d := range diags {
+		actions, err := c.CodeActions(path, d.Range, []Diagnostic{d})
+		if err != nil {
+			return "", false, err
+		}
+		action, ok := firstQuickFix(actions)
+		if !ok {
+			continue
+		}
+		patches, err := ApplyCodeAction(action, current)
+		if err != nil {
+			return "", false, err
+		}
+		for p, text := range patches {
+			current[p] = text
+		}
+		delete(remaining, i)
+	}
+
+	return current[path], len(remaining) == 0, nil
+}
+
+func firstQuickFix(actions []CodeAction) (CodeAction, bool) {
+	for _, a := range actions {
+		if a.Kind == "quickfix" || a.Kind == "source.organizeImports" {
+			return a, true
+		}
+	}
+	if len(actions) > 0 {
+		return actions[0], true
+	}
+	return CodeAction{}, false
+}
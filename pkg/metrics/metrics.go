@@ -0,0 +1,100 @@
+// Package metrics lets the patcher pipeline report operational statistics
+// - files scanned, fixes applied per rule, errors, per-file processing
+// duration - without the pipeline itself depending on a specific metrics
+// backend. Recorder is the seam: the default Metrics value is a no-op, and
+// a real backend (Prometheus, StatsD, whatever an operator wants) plugs in
+// through WithRecorder without this package or its callers ever importing
+// it. See prometheus.go (behind the "metrics" build tag) for the
+// Prometheus-backed Recorder this was designed for.
+package metrics
+
+import "time"
+
+// Recorder receives the patcher pipeline's operational events. All methods
+// must be safe to call from multiple goroutines, matching how
+// RunUnusedPass processes directories concurrently-capable callers might
+// invoke it from.
+type Recorder interface {
+	// FileScanned records that one file was considered for a fix,
+	// regardless of whether a fix was found or applied.
+	FileScanned()
+	// FixApplied records that a fix of the given rule (a gofix Category,
+	// kept as a string here so this package doesn't need to depend on
+	// gofix) was applied.
+	FixApplied(rule string)
+	// PatchError records that the pipeline encountered an error it
+	// couldn't recover from for the file or package being processed.
+	PatchError()
+	// ObserveDuration records how long one file took to process.
+	ObserveDuration(d time.Duration)
+}
+
+// noopRecorder is the Recorder every Metrics starts with, so a caller that
+// never configures a real backend pays no cost beyond the interface calls
+// themselves.
+type noopRecorder struct{}
+
+func (noopRecorder) FileScanned()                  {}
+func (noopRecorder) FixApplied(rule string)        {}
+func (noopRecorder) PatchError()                   {}
+func (noopRecorder) ObserveDuration(time.Duration) {}
+
+// Metrics wraps a Recorder, providing a stable call surface for the
+// patcher pipeline to report against regardless of which backend (or no
+// backend) a caller configured.
+type Metrics struct {
+	rec Recorder
+}
+
+// Option configures a Metrics value returned by New.
+type Option func(*Metrics)
+
+// WithRecorder sets the Recorder a Metrics value reports to. Without it,
+// New returns a Metrics backed by a no-op Recorder.
+func WithRecorder(r Recorder) Option {
+	return func(m *Metrics) { m.rec = r }
+}
+
+// New returns a Metrics configured by opts, defaulting to a no-op Recorder.
+func New(opts ...Option) *Metrics {
+	m := &Metrics{rec: noopRecorder{}}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// FileScanned records that one file was considered for a fix. m may be
+// nil, in which case this is a no-op - callers that never configured
+// metrics shouldn't have to guard every call site.
+func (m *Metrics) FileScanned() {
+	if m == nil {
+		return
+	}
+	m.rec.FileScanned()
+}
+
+// FixApplied records that a fix of the given rule was applied. m may be
+// nil.
+func (m *Metrics) FixApplied(rule string) {
+	if m == nil {
+		return
+	}
+	m.rec.FixApplied(rule)
+}
+
+// PatchError records an unrecoverable pipeline error. m may be nil.
+func (m *Metrics) PatchError() {
+	if m == nil {
+		return
+	}
+	m.rec.PatchError()
+}
+
+// ObserveDuration records how long one file took to process. m may be nil.
+func (m *Metrics) ObserveDuration(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.rec.ObserveDuration(d)
+}
@@ -0,0 +1,72 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/metrics"
+)
+
+type fakeRecorder struct {
+	filesScanned int
+	fixesApplied map[string]int
+	errors       int
+	durations    []time.Duration
+}
+
+func newFakeRecorder() *fakeRecorder {
+	return &fakeRecorder{fixesApplied: map[string]int{}}
+}
+
+func (f *fakeRecorder) FileScanned()           { f.filesScanned++ }
+func (f *fakeRecorder) FixApplied(rule string) { f.fixesApplied[rule]++ }
+func (f *fakeRecorder) PatchError()            { f.errors++ }
+func (f *fakeRecorder) ObserveDuration(d time.Duration) {
+	f.durations = append(f.durations, d)
+}
+
+func TestMetricsDelegatesToConfiguredRecorder(t *testing.T) {
+	rec := newFakeRecorder()
+	m := metrics.New(metrics.WithRecorder(rec))
+
+	m.FileScanned()
+	m.FileScanned()
+	m.FixApplied("unused-import")
+	m.FixApplied("unused-import")
+	m.FixApplied("missing-import")
+	m.PatchError()
+	m.ObserveDuration(5 * time.Millisecond)
+
+	if rec.filesScanned != 2 {
+		t.Errorf("filesScanned = %d, want 2", rec.filesScanned)
+	}
+	if rec.fixesApplied["unused-import"] != 2 || rec.fixesApplied["missing-import"] != 1 {
+		t.Errorf("fixesApplied = %+v, want unused-import:2 missing-import:1", rec.fixesApplied)
+	}
+	if rec.errors != 1 {
+		t.Errorf("errors = %d, want 1", rec.errors)
+	}
+	if len(rec.durations) != 1 || rec.durations[0] != 5*time.Millisecond {
+		t.Errorf("durations = %v, want [5ms]", rec.durations)
+	}
+}
+
+func TestMetricsDefaultsToNoop(t *testing.T) {
+	m := metrics.New()
+	// None of these should panic; there's nothing else to assert against a
+	// no-op Recorder.
+	m.FileScanned()
+	m.FixApplied("unused-import")
+	m.PatchError()
+	m.ObserveDuration(time.Second)
+}
+
+func TestNilMetricsIsANoop(t *testing.T) {
+	var m *metrics.Metrics
+	// A caller that never configured metrics shouldn't have to guard every
+	// call site with a nil check.
+	m.FileScanned()
+	m.FixApplied("unused-import")
+	m.PatchError()
+	m.ObserveDuration(time.Second)
+}
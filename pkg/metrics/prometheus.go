@@ -0,0 +1,65 @@
+//go:build metrics
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// prometheusRecorder is the Prometheus-backed Recorder WithMetrics wires
+// in. Everything is registered under the repopatcher_ namespace so it
+// doesn't collide with metrics a host process registers for itself.
+type prometheusRecorder struct {
+	filesScanned prometheus.Counter
+	fixesApplied *prometheus.CounterVec
+	patchErrors  prometheus.Counter
+	duration     prometheus.Histogram
+}
+
+// newPrometheusRecorder creates and registers the collectors against reg.
+func newPrometheusRecorder(reg prometheus.Registerer) *prometheusRecorder {
+	r := &prometheusRecorder{
+		filesScanned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "repopatcher",
+			Name:      "files_scanned_total",
+			Help:      "Total number of files considered for a fix.",
+		}),
+		fixesApplied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "repopatcher",
+			Name:      "fixes_applied_total",
+			Help:      "Total number of fixes applied, by rule.",
+		}, []string{"rule"}),
+		patchErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "repopatcher",
+			Name:      "errors_total",
+			Help:      "Total number of unrecoverable errors the patcher pipeline encountered.",
+		}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "repopatcher",
+			Name:      "file_processing_duration_seconds",
+			Help:      "Time spent processing a single file.",
+		}),
+	}
+	reg.MustRegister(r.filesScanned, r.fixesApplied, r.patchErrors, r.duration)
+	return r
+}
+
+func (r *prometheusRecorder) FileScanned() { r.filesScanned.Inc() }
+
+func (r *prometheusRecorder) FixApplied(rule string) { r.fixesApplied.WithLabelValues(rule).Inc() }
+
+func (r *prometheusRecorder) PatchError() { r.patchErrors.Inc() }
+
+func (r *prometheusRecorder) ObserveDuration(d time.Duration) { r.duration.Observe(d.Seconds()) }
+
+// WithMetrics wires a Prometheus-backed Recorder into a Metrics value,
+// registering its collectors against reg. Building with this file requires
+// the "metrics" build tag (go build -tags metrics ./...) and
+// github.com/prometheus/client_golang in go.mod - the rest of the pipeline
+// builds and runs without either, since Metrics talks to the Recorder
+// interface, not this package's types.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return WithRecorder(newPrometheusRecorder(reg))
+}
@@ -0,0 +1,29 @@
+//go:build metrics
+
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestWithMetricsRegistersAndCountsUnderRepopatcherNamespace(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(metrics.WithMetrics(reg))
+
+	m.FileScanned()
+	m.FileScanned()
+	m.FixApplied("unused-import")
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP repopatcher_files_scanned_total Total number of files considered for a fix.
+# TYPE repopatcher_files_scanned_total counter
+repopatcher_files_scanned_total 2
+`), "repopatcher_files_scanned_total"); err != nil {
+		t.Errorf("unexpected collected metrics: %v", err)
+	}
+}
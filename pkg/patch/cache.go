@@ -0,0 +1,85 @@
+package patch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CacheEntry is what PatchCache remembers for one (file, content-hash)
+// pair. Result alone doesn't carry the patched bytes it produced, so the
+// cache keeps them alongside Results - without them, a cache hit could
+// only skip re-reporting a fix, not re-parsing and re-applying it.
+type CacheEntry struct {
+	Results []Result
+	Patched []byte
+}
+
+// PatchCache maps a file path and the sha256 of its current contents to
+// the fix that content previously produced, so re-running the patcher
+// over a file whose contents haven't changed since the last run can reuse
+// that fix instead of reparsing the file's AST.
+type PatchCache struct {
+	entries map[string]map[string]CacheEntry // file -> content hash -> entry
+}
+
+// NewPatchCache returns an empty cache.
+func NewPatchCache() *PatchCache {
+	return &PatchCache{entries: map[string]map[string]CacheEntry{}}
+}
+
+// LoadPatchCache reads a PatchCache previously written by Save. A missing
+// file is not an error - it just means a cold cache, same as
+// gofix.LoadConfig's treatment of a missing .repopatcher.yaml.
+func LoadPatchCache(path string) (*PatchCache, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewPatchCache(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("patch: read cache %s: %w", path, err)
+	}
+	entries := map[string]map[string]CacheEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("patch: parse cache %s: %w", path, err)
+	}
+	return &PatchCache{entries: entries}, nil
+}
+
+// Save writes c to path as JSON.
+func (c *PatchCache) Save(path string) error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("patch: marshal cache: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("patch: write cache %s: %w", path, err)
+	}
+	return nil
+}
+
+// HashContent returns the cache key content hashes to.
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Lookup returns the entry cached for file at content's current hash, if
+// any.
+func (c *PatchCache) Lookup(file string, content []byte) (CacheEntry, bool) {
+	byHash, ok := c.entries[file]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	entry, ok := byHash[HashContent(content)]
+	return entry, ok
+}
+
+// Store records entry for file at content's hash. A file only ever needs
+// its current hash remembered, so this replaces whatever was cached for
+// that file's previous contents.
+func (c *PatchCache) Store(file string, content []byte, entry CacheEntry) {
+	c.entries[file] = map[string]CacheEntry{HashContent(content): entry}
+}
@@ -0,0 +1,68 @@
+package patch_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestPatchCacheLookupMissesOnContentChange(t *testing.T) {
+	c := patch.NewPatchCache()
+	entry := patch.CacheEntry{
+		Results: []patch.Result{{File: "a.go", Kind: patch.KindRemoveImport, Changed: true}},
+		Patched: []byte("package a\n"),
+	}
+	c.Store("a.go", []byte("package a\n\nimport \"fmt\"\n"), entry)
+
+	if _, ok := c.Lookup("a.go", []byte("package a\n\nimport \"fmt\"\n")); !ok {
+		t.Fatal("expected a hit for the content Store was given")
+	}
+	if _, ok := c.Lookup("a.go", []byte("package a\n\nimport \"os\"\n")); ok {
+		t.Fatal("expected a miss once the file's content changed")
+	}
+	if _, ok := c.Lookup("b.go", []byte("package a\n\nimport \"fmt\"\n")); ok {
+		t.Fatal("expected a miss for a different file path")
+	}
+}
+
+func TestPatchCacheSaveLoadRoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	c := patch.NewPatchCache()
+	entry := patch.CacheEntry{
+		Results: []patch.Result{{File: "a.go", Kind: patch.KindBlankUse, Changed: true, BeforeStart: 3, BeforeEnd: 3, AfterStart: 3, AfterEnd: 3}},
+		Patched: []byte("package a\n\nfunc f() {\n\t_ = 1\n}\n"),
+	}
+	c.Store("a.go", []byte("package a\n\nfunc f() {\n\t1\n}\n"), entry)
+
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := patch.LoadPatchCache(path)
+	if err != nil {
+		t.Fatalf("LoadPatchCache: %v", err)
+	}
+
+	got, ok := loaded.Lookup("a.go", []byte("package a\n\nfunc f() {\n\t1\n}\n"))
+	if !ok {
+		t.Fatal("expected the saved entry to round-trip")
+	}
+	if string(got.Patched) != string(entry.Patched) {
+		t.Errorf("Patched = %q, want %q", got.Patched, entry.Patched)
+	}
+	if len(got.Results) != 1 || got.Results[0].Kind != patch.KindBlankUse {
+		t.Errorf("Results = %+v, want one KindBlankUse result", got.Results)
+	}
+}
+
+func TestLoadPatchCacheMissingFileIsEmpty(t *testing.T) {
+	c, err := patch.LoadPatchCache(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("LoadPatchCache: %v", err)
+	}
+	if _, ok := c.Lookup("a.go", []byte("anything")); ok {
+		t.Fatal("expected a cold cache to miss everything")
+	}
+}
@@ -0,0 +1,189 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each hunk,
+// matching `diff -u`'s default.
+const diffContext = 3
+
+// UnifiedDiff renders a standard `diff -u` style unified diff between
+// before and after. path is used for both the "--- a/<path>" and
+// "+++ b/<path>" headers. It returns "" if before and after are identical.
+func UnifiedDiff(path string, before, after []byte) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+	hunks := buildHunks(ops, diffContext)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n", path)
+	fmt.Fprintf(&sb, "+++ b/%s\n", path)
+	for _, h := range hunks {
+		h.writeTo(&sb)
+	}
+	return sb.String()
+}
+
+func splitLines(b []byte) []string {
+	s := strings.TrimSuffix(string(b), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffOp is one line of a unified diff body: ' ' for context, '-' for a
+// line only in before, '+' for a line only in after.
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// diffLines computes a line-level edit script from a to b via the
+// standard longest-common-subsequence table, the same approach GNU diff
+// falls back to for small inputs. It's O(len(a)*len(b)), which is fine for
+// the source files this package patches.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// annotatedOp pairs a diffOp with the 1-based line number it would occupy
+// in the old and new file if it were an unchanged line there - i.e. the
+// position right before the op is applied. This makes hunk header math
+// (which line a hunk starts on) a matter of reading the first op's fields.
+type annotatedOp struct {
+	op      diffOp
+	oldLine int
+	newLine int
+}
+
+func annotate(ops []diffOp) []annotatedOp {
+	ann := make([]annotatedOp, len(ops))
+	oldLine, newLine := 1, 1
+	for idx, op := range ops {
+		ann[idx] = annotatedOp{op: op, oldLine: oldLine, newLine: newLine}
+		switch op.kind {
+		case ' ':
+			oldLine++
+			newLine++
+		case '-':
+			oldLine++
+		case '+':
+			newLine++
+		}
+	}
+	return ann
+}
+
+// hunk is one @@ ... @@ section of a unified diff.
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+	lines              []diffOp
+}
+
+func (h hunk) writeTo(sb *strings.Builder) {
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", h.oldStart, h.oldCount, h.newStart, h.newCount)
+	for _, op := range h.lines {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.text)
+		sb.WriteByte('\n')
+	}
+}
+
+// buildHunks groups ops into hunks, padding each change with up to
+// context lines of surrounding unchanged text and merging hunks whose
+// padding would otherwise overlap, the same way `diff -u` does.
+func buildHunks(ops []diffOp, context int) []hunk {
+	ann := annotate(ops)
+
+	var changed []int
+	for idx, a := range ann {
+		if a.op.kind != ' ' {
+			changed = append(changed, idx)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	var groups [][2]int
+	gs, ge := changed[0], changed[0]
+	for _, idx := range changed[1:] {
+		if idx-ge <= 2*context {
+			ge = idx
+		} else {
+			groups = append(groups, [2]int{gs, ge})
+			gs, ge = idx, idx
+		}
+	}
+	groups = append(groups, [2]int{gs, ge})
+
+	hunks := make([]hunk, 0, len(groups))
+	for _, g := range groups {
+		start := g[0] - context
+		if start < 0 {
+			start = 0
+		}
+		end := g[1] + context
+		if end >= len(ann) {
+			end = len(ann) - 1
+		}
+
+		h := hunk{oldStart: ann[start].oldLine, newStart: ann[start].newLine}
+		for _, a := range ann[start : end+1] {
+			h.lines = append(h.lines, a.op)
+			if a.op.kind == ' ' || a.op.kind == '-' {
+				h.oldCount++
+			}
+			if a.op.kind == ' ' || a.op.kind == '+' {
+				h.newCount++
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
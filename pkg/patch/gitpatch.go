@@ -0,0 +1,170 @@
+package patch
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ErrHashMismatch is returned by ImportPatch when a file's "index" line
+// doesn't match the content reconstructed from that file's own hunks,
+// meaning the patch text was hand-edited or corrupted after it was
+// generated.
+var ErrHashMismatch = errors.New("patch: index hash does not match patched content")
+
+// FilePatch is one file's before/after content as reconstructed from a
+// git-format patch by ImportPatch.
+type FilePatch struct {
+	Path       string
+	OldContent []byte
+	NewContent []byte
+}
+
+// ExportPatch writes results as a multi-file patch in the format `git diff`
+// produces: a "diff --git a/... b/..." header and an "index old..new mode"
+// line ahead of each result's unified diff. Results without a DiffOutput -
+// i.e. ones produced outside a --dry-run pass, see RunOptions.DryRun - are
+// skipped, since a Result doesn't otherwise retain the whole-file content a
+// patch header could hash.
+func ExportPatch(results []Result, w io.Writer) error {
+	for _, res := range results {
+		if res.DiffOutput == "" {
+			continue
+		}
+		before, after := reconstructHunkContent(res.DiffOutput)
+		path := filepath.ToSlash(res.File)
+		if _, err := fmt.Fprintf(w, "diff --git a/%s b/%s\n", path, path); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "index %s..%s 100644\n", blobHash(before), blobHash(after)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, res.DiffOutput); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blobHash hashes content the way git hashes a blob object: sha1 of
+// "blob <len>\x00<content>". Since ExportPatch only ever has the portion of
+// a file visible in its hunks (not the whole file), this is only a true git
+// blob hash when a Result's diff covers the entire file - but it's always
+// self-consistent with what ImportPatch recomputes from the same hunks,
+// which is what ImportPatch's ErrHashMismatch check relies on.
+func blobHash(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// reconstructHunkContent recovers the old and new file content visible in
+// diffOutput's hunks (a UnifiedDiff-shaped "--- a/...\n+++ b/...\n@@ ...
+// @@\n..." string): context and removed lines make up old, context and
+// added lines make up new. CRLF source lines survive this round trip
+// unchanged, because UnifiedDiff only ever splits lines on "\n", leaving a
+// trailing "\r" attached to the line text it stores.
+func reconstructHunkContent(diffOutput string) (before, after []byte) {
+	var beforeLines, afterLines []string
+	for _, line := range strings.Split(diffOutput, "\n") {
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case ' ':
+			beforeLines = append(beforeLines, line[1:])
+			afterLines = append(afterLines, line[1:])
+		case '-':
+			if strings.HasPrefix(line, "---") {
+				continue
+			}
+			beforeLines = append(beforeLines, line[1:])
+		case '+':
+			if strings.HasPrefix(line, "+++") {
+				continue
+			}
+			afterLines = append(afterLines, line[1:])
+		}
+	}
+	return []byte(strings.Join(beforeLines, "\n")), []byte(strings.Join(afterLines, "\n"))
+}
+
+// ImportPatch parses r as a multi-file patch in the format ExportPatch
+// writes and returns the files it touches. For each file it recomputes the
+// "index old..new" hashes from the patch's own hunks and returns
+// ErrHashMismatch if either no longer matches, so a hand-edited or
+// corrupted patch is rejected before anything tries to apply it.
+func ImportPatch(r io.Reader) ([]FilePatch, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var patches []FilePatch
+	var cur *FilePatch
+	var oldHash, newHash string
+	var body []string
+	inHunk := false
+
+	flush := func() error {
+		if cur == nil {
+			return nil
+		}
+		before, after := reconstructHunkContent(strings.Join(body, "\n") + "\n")
+		if blobHash(before) != oldHash || blobHash(after) != newHash {
+			return fmt.Errorf("%s: %w", cur.Path, ErrHashMismatch)
+		}
+		cur.OldContent = before
+		cur.NewContent = after
+		patches = append(patches, *cur)
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git a/"):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			rest := strings.TrimPrefix(line, "diff --git a/")
+			parts := strings.SplitN(rest, " b/", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("patch: malformed diff --git header %q", line)
+			}
+			cur = &FilePatch{Path: parts[0]}
+			oldHash, newHash = "", ""
+			body = nil
+			inHunk = false
+		case strings.HasPrefix(line, "GIT binary patch"):
+			return nil, fmt.Errorf("patch: binary patch for %s not supported", cur.Path)
+		case strings.HasPrefix(line, "index "):
+			fields := strings.Fields(strings.TrimPrefix(line, "index "))
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("patch: malformed index line %q", line)
+			}
+			hashes := strings.SplitN(fields[0], "..", 2)
+			if len(hashes) != 2 {
+				return nil, fmt.Errorf("patch: malformed index line %q", line)
+			}
+			oldHash, newHash = hashes[0], hashes[1]
+		case strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ "):
+			// Already implied by the diff --git header; nothing to record.
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			body = append(body, line)
+		case inHunk:
+			body = append(body, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return patches, nil
+}
@@ -0,0 +1,81 @@
+package patch_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// TestExportImportPatchRoundTrip exercises ExportPatch/ImportPatch against
+// the E003 scenario's missing-import fix: utils.go before a `fmt` import is
+// added, and the same file after.
+func TestExportImportPatchRoundTrip(t *testing.T) {
+	repoFile := filepath.Join("..", "..", "scenarios", "E003_go_missing_import", "repo", "utils.go")
+	before, err := os.ReadFile(repoFile)
+	if err != nil {
+		t.Fatalf("ReadFile(repo): %v", err)
+	}
+	after := bytes.Replace(before, []byte("package main\n"), []byte("package main\n\nimport \"fmt\"\n"), 1)
+	if bytes.Equal(before, after) {
+		t.Fatal("test fixture didn't change after adding the import")
+	}
+
+	diff := patch.UnifiedDiff("utils.go", before, after)
+	if diff == "" {
+		t.Fatal("UnifiedDiff returned no diff for a changed file")
+	}
+	results := []patch.Result{{File: "utils.go", Kind: patch.KindAddImport, Changed: true, DiffOutput: diff}}
+
+	var buf bytes.Buffer
+	if err := patch.ExportPatch(results, &buf); err != nil {
+		t.Fatalf("ExportPatch: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "diff --git a/utils.go b/utils.go\n") {
+		t.Fatalf("got patch:\n%s\nwant it to start with a diff --git header", out)
+	}
+	if !strings.Contains(out, "index ") {
+		t.Errorf("got patch:\n%s\nwant an index line", out)
+	}
+
+	filePatches, err := patch.ImportPatch(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("ImportPatch: %v", err)
+	}
+	if len(filePatches) != 1 {
+		t.Fatalf("got %d FilePatches, want 1", len(filePatches))
+	}
+	fp := filePatches[0]
+	if fp.Path != "utils.go" {
+		t.Errorf("got Path=%q, want utils.go", fp.Path)
+	}
+	if !bytes.Contains(fp.NewContent, []byte(`import "fmt"`)) {
+		t.Errorf("got NewContent=%q, want it to contain the new import", fp.NewContent)
+	}
+}
+
+// TestImportPatchRejectsTamperedHash confirms a patch whose hunk body was
+// edited after export - so its hunks no longer hash to the index line's
+// claimed content - is rejected rather than silently imported.
+func TestImportPatchRejectsTamperedHash(t *testing.T) {
+	before := []byte("package main\n\nfunc f() {}\n")
+	after := []byte("package main\n\nfunc f() { println(\"hi\") }\n")
+	diff := patch.UnifiedDiff("f.go", before, after)
+	results := []patch.Result{{File: "f.go", Changed: true, DiffOutput: diff}}
+
+	var buf bytes.Buffer
+	if err := patch.ExportPatch(results, &buf); err != nil {
+		t.Fatalf("ExportPatch: %v", err)
+	}
+
+	tampered := strings.Replace(buf.String(), `println("hi")`, `println("tampered")`, 1)
+	if _, err := patch.ImportPatch(strings.NewReader(tampered)); err == nil {
+		t.Fatal("ImportPatch accepted a patch whose hunk no longer matches its index hash")
+	} else if !strings.Contains(err.Error(), patch.ErrHashMismatch.Error()) {
+		t.Errorf("got err %v, want it to wrap ErrHashMismatch", err)
+	}
+}
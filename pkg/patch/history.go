@@ -0,0 +1,89 @@
+package patch
+
+import (
+	"fmt"
+	"os"
+)
+
+// Snapshot is one entry in a History's undo/redo stack: the file an edit
+// touched, and its content immediately before and after that edit.
+type Snapshot struct {
+	File   string
+	Before []byte
+	After  []byte
+}
+
+// History is a file-level undo/redo stack for an interactive patch
+// session: each Apply records what changed so a later Undo can restore
+// the prior content and a later Redo can reapply it, independent of
+// whatever Transaction already committed the change to disk.
+type History struct {
+	// MaxDepth caps how many snapshots Undo can step back through; the
+	// oldest snapshot is dropped once Apply would exceed it. Zero means
+	// unlimited.
+	MaxDepth int
+
+	undo []Snapshot
+	redo []Snapshot
+}
+
+// NewHistory returns a History capped at maxDepth snapshots (0 for
+// unlimited).
+func NewHistory(maxDepth int) *History {
+	return &History{MaxDepth: maxDepth}
+}
+
+// Apply records that r.File changed from before to after, pushing a
+// Snapshot onto the undo stack and clearing the redo stack - the same
+// rule a text editor's undo history follows: a fresh edit invalidates
+// whatever redo history came from a different branch of edits.
+func (h *History) Apply(r Result, before, after []byte) {
+	h.undo = append(h.undo, Snapshot{File: r.File, Before: before, After: after})
+	if h.MaxDepth > 0 && len(h.undo) > h.MaxDepth {
+		h.undo = h.undo[len(h.undo)-h.MaxDepth:]
+	}
+	h.redo = nil
+}
+
+// Undo restores the most recently applied snapshot's file to its Before
+// content and moves that snapshot onto the redo stack.
+func (h *History) Undo() error {
+	if len(h.undo) == 0 {
+		return fmt.Errorf("patch: history: nothing to undo")
+	}
+	s := h.undo[len(h.undo)-1]
+	if err := os.WriteFile(s.File, s.Before, 0o644); err != nil {
+		return fmt.Errorf("patch: history: undo %s: %w", s.File, err)
+	}
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, s)
+	return nil
+}
+
+// Redo reapplies the most recently undone snapshot's After content and
+// moves it back onto the undo stack.
+func (h *History) Redo() error {
+	if len(h.redo) == 0 {
+		return fmt.Errorf("patch: history: nothing to redo")
+	}
+	s := h.redo[len(h.redo)-1]
+	if err := os.WriteFile(s.File, s.After, 0o644); err != nil {
+		return fmt.Errorf("patch: history: redo %s: %w", s.File, err)
+	}
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, s)
+	return nil
+}
+
+// UndoAll undoes every snapshot on the undo stack, restoring every
+// touched file to the content it had before this History's first Apply.
+// It stops and returns the first error Undo reports, leaving whatever was
+// undone so far in place.
+func (h *History) UndoAll() error {
+	for len(h.undo) > 0 {
+		if err := h.Undo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package patch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestHistoryUndoRedoRoundTrips(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.go")
+	before := []byte("package a\n\nimport \"fmt\"\n")
+	after := []byte("package a\n")
+	if err := os.WriteFile(file, after, 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+
+	h := patch.NewHistory(0)
+	h.Apply(patch.Result{File: file}, before, after)
+
+	if err := h.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != string(before) {
+		t.Errorf("after Undo, file = %q, want %q", got, before)
+	}
+
+	if err := h.Redo(); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != string(after) {
+		t.Errorf("after Redo, file = %q, want %q", got, after)
+	}
+}
+
+func TestHistoryApplyClearsRedoStack(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(file, []byte("v2"), 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+
+	h := patch.NewHistory(0)
+	h.Apply(patch.Result{File: file}, []byte("v1"), []byte("v2"))
+	if err := h.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+
+	h.Apply(patch.Result{File: file}, []byte("v1"), []byte("v3"))
+	if err := h.Redo(); err == nil {
+		t.Fatal("expected Redo to fail: Apply should have cleared the stale redo entry")
+	}
+}
+
+func TestHistoryMaxDepthEvictsOldestSnapshot(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.go")
+
+	h := patch.NewHistory(1)
+	h.Apply(patch.Result{File: file}, []byte("v0"), []byte("v1"))
+	h.Apply(patch.Result{File: file}, []byte("v1"), []byte("v2"))
+
+	if err := os.WriteFile(file, []byte("v2"), 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+	if err := h.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != "v1" {
+		t.Errorf("after Undo, file = %q, want %q", got, "v1")
+	}
+	if err := h.Undo(); err == nil {
+		t.Fatal("expected the second Undo to fail: MaxDepth 1 should have evicted the first snapshot")
+	}
+}
+
+func TestHistoryUndoAllRestoresEveryFile(t *testing.T) {
+	fileA := filepath.Join(t.TempDir(), "a.go")
+	fileB := filepath.Join(t.TempDir(), "b.go")
+	os.WriteFile(fileA, []byte("a-after"), 0o644)
+	os.WriteFile(fileB, []byte("b-after"), 0o644)
+
+	h := patch.NewHistory(0)
+	h.Apply(patch.Result{File: fileA}, []byte("a-before"), []byte("a-after"))
+	h.Apply(patch.Result{File: fileB}, []byte("b-before"), []byte("b-after"))
+
+	if err := h.UndoAll(); err != nil {
+		t.Fatalf("UndoAll: %v", err)
+	}
+	if got, _ := os.ReadFile(fileA); string(got) != "a-before" {
+		t.Errorf("fileA = %q, want a-before", got)
+	}
+	if got, _ := os.ReadFile(fileB); string(got) != "b-before" {
+		t.Errorf("fileB = %q, want b-before", got)
+	}
+}
+
+func TestHistoryUndoOnEmptyStackErrors(t *testing.T) {
+	h := patch.NewHistory(0)
+	if err := h.Undo(); err == nil {
+		t.Fatal("expected Undo on an empty stack to error")
+	}
+	if err := h.Redo(); err == nil {
+		t.Fatal("expected Redo on an empty stack to error")
+	}
+}
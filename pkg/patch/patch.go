@@ -0,0 +1,119 @@
+// Package patch defines the structured result every gofix entry point that
+// mutates source returns, so callers can build summaries, drive
+// interactive UIs, or write assertions in tests without re-diffing files
+// by hand.
+package patch
+
+import "strings"
+
+// Kind identifies the category of edit a Result records.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindAddImport
+	KindRemoveImport
+	KindBlankImport
+	KindBlankUse
+	KindReplaceCall
+	KindPluginFix
+	KindSSR
+	KindAddMethodStub
+	KindRename
+	KindContextPropagation
+	KindPackageExtraction
+	KindTestStub
+	KindExtractConstant
+)
+
+// String renders k the way a summary or log line would want to show it.
+func (k Kind) String() string {
+	switch k {
+	case KindAddImport:
+		return "add-import"
+	case KindRemoveImport:
+		return "remove-import"
+	case KindBlankImport:
+		return "blank-import"
+	case KindBlankUse:
+		return "blank-use"
+	case KindReplaceCall:
+		return "replace-call"
+	case KindPluginFix:
+		return "plugin-fix"
+	case KindSSR:
+		return "ssr"
+	case KindAddMethodStub:
+		return "add-method-stub"
+	case KindRename:
+		return "rename"
+	case KindContextPropagation:
+		return "context-propagation"
+	case KindPackageExtraction:
+		return "package-extraction"
+	case KindTestStub:
+		return "test-stub"
+	case KindExtractConstant:
+		return "extract-constant"
+	default:
+		return "unknown"
+	}
+}
+
+// Result records one edit applied to File: what kind of fix it was, the
+// 1-based line range in the original source it replaced (BeforeStart,
+// BeforeEnd) and the 1-based line range of the replacement in the patched
+// output (AfterStart, AfterEnd), and whether the edit actually changed
+// anything. A no-op edit (Changed false) still reports a Result so callers
+// can tell "considered and declined" apart from "never attempted".
+//
+// DiffOutput is only populated in dry-run mode, where the edit is never
+// written to disk: it holds the unified diff (see UnifiedDiff) a caller
+// would otherwise have to reconstruct itself from the before/after bytes.
+//
+// Provenance is populated by passes that record audit-trail sidecar files
+// (see WriteProvenance): the same entry written to disk, kept here too so
+// a caller doesn't have to re-read it back from the file it was just
+// written to.
+type Result struct {
+	File        string
+	Kind        Kind
+	BeforeStart int
+	BeforeEnd   int
+	AfterStart  int
+	AfterEnd    int
+	Changed     bool
+	DiffOutput  string
+	Provenance  []ProvenanceEntry
+}
+
+// DiffLines compares before and after line-by-line and returns the range of
+// lines that differ, using the longest common prefix and suffix of lines to
+// bracket a single contiguous edit - the same heuristic astedit's Ops
+// produce, since each Op touches one import block or statement at a time.
+// It is not a general-purpose diff: a change with two disjoint edits will
+// report the whole span between them as changed.
+func DiffLines(before, after []byte) (beforeStart, beforeEnd, afterStart, afterEnd int) {
+	a := strings.Split(string(before), "\n")
+	b := strings.Split(string(after), "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	beforeStart, beforeEnd = prefix+1, len(a)-suffix
+	afterStart, afterEnd = prefix+1, len(b)-suffix
+	if beforeEnd < beforeStart {
+		beforeEnd = beforeStart
+	}
+	if afterEnd < afterStart {
+		afterEnd = afterStart
+	}
+	return beforeStart, beforeEnd, afterStart, afterEnd
+}
@@ -0,0 +1,68 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// HunkRange is the 1-based before/after line range a single edit touched -
+// the same shape as Result's Before/After fields, duplicated here so a
+// ProvenanceEntry is self-contained once it's written out to its own
+// sidecar file, independent of the Result that produced it.
+type HunkRange struct {
+	BeforeStart int
+	BeforeEnd   int
+	AfterStart  int
+	AfterEnd    int
+}
+
+// ProvenanceEntry records which rule produced one edit and when, for audit
+// trails: not just that a file changed, but which specific rule caused a
+// given hunk to change.
+type ProvenanceEntry struct {
+	Rule        string
+	RuleVersion string
+	AppliedAt   time.Time
+	Hunk        HunkRange
+}
+
+// WriteProvenance appends entries to the sidecar JSON file at path,
+// preserving whatever entries ReadProvenance would already find there -
+// callers build up one file's provenance across several patch passes, not
+// just the most recent one.
+func WriteProvenance(path string, entries []ProvenanceEntry) error {
+	existing, err := ReadProvenance(path)
+	if err != nil {
+		return err
+	}
+	all := append(existing, entries...)
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("patch: marshal provenance %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("patch: write provenance %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadProvenance reads and parses the sidecar JSON file at path. A missing
+// file is not an error - no fix has recorded provenance there yet - and
+// returns a nil slice.
+func ReadProvenance(path string) ([]ProvenanceEntry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("patch: read provenance %s: %w", path, err)
+	}
+	var entries []ProvenanceEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("patch: parse provenance %s: %w", path, err)
+	}
+	return entries, nil
+}
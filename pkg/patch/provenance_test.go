@@ -0,0 +1,78 @@
+package patch_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestProvenanceRoundTripsThroughJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.go.provenance.json")
+
+	entries := []patch.ProvenanceEntry{
+		{
+			Rule:        "unused-import",
+			RuleVersion: "1",
+			AppliedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Hunk:        patch.HunkRange{BeforeStart: 3, BeforeEnd: 3, AfterStart: 0, AfterEnd: 0},
+		},
+	}
+
+	if err := patch.WriteProvenance(path, entries); err != nil {
+		t.Fatalf("WriteProvenance: %v", err)
+	}
+
+	got, err := patch.ReadProvenance(path)
+	if err != nil {
+		t.Fatalf("ReadProvenance: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ReadProvenance returned %d entries, want 1", len(got))
+	}
+	if got[0].Rule != "unused-import" || got[0].RuleVersion != "1" {
+		t.Errorf("got %+v, want Rule=unused-import RuleVersion=1", got[0])
+	}
+	if !got[0].AppliedAt.Equal(entries[0].AppliedAt) {
+		t.Errorf("AppliedAt = %v, want %v", got[0].AppliedAt, entries[0].AppliedAt)
+	}
+	if got[0].Hunk != entries[0].Hunk {
+		t.Errorf("Hunk = %+v, want %+v", got[0].Hunk, entries[0].Hunk)
+	}
+}
+
+func TestWriteProvenanceAppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.go.provenance.json")
+
+	first := []patch.ProvenanceEntry{{Rule: "unused-import", RuleVersion: "1", AppliedAt: time.Unix(1, 0).UTC()}}
+	second := []patch.ProvenanceEntry{{Rule: "unused-var", RuleVersion: "1", AppliedAt: time.Unix(2, 0).UTC()}}
+
+	if err := patch.WriteProvenance(path, first); err != nil {
+		t.Fatalf("WriteProvenance (first): %v", err)
+	}
+	if err := patch.WriteProvenance(path, second); err != nil {
+		t.Fatalf("WriteProvenance (second): %v", err)
+	}
+
+	got, err := patch.ReadProvenance(path)
+	if err != nil {
+		t.Fatalf("ReadProvenance: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ReadProvenance returned %d entries, want 2 (one appended)", len(got))
+	}
+	if got[0].Rule != "unused-import" || got[1].Rule != "unused-var" {
+		t.Errorf("got rules %q, %q, want unused-import, unused-var", got[0].Rule, got[1].Rule)
+	}
+}
+
+func TestReadProvenanceMissingFileIsEmpty(t *testing.T) {
+	got, err := patch.ReadProvenance(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("ReadProvenance: %v", err)
+	}
+	if got != nil {
+		t.Errorf("ReadProvenance = %+v, want nil for a missing file", got)
+	}
+}
@@ -0,0 +1,94 @@
+package patch
+
+import (
+	"fmt"
+	"os"
+)
+
+// Transaction buffers writes to a set of files in memory until Commit
+// flushes them all to disk, so a multi-file fix either lands completely or
+// not at all: a failure partway through proposing fixes for file K never
+// leaves files 1..K-1 written while K itself is skipped.
+type Transaction struct {
+	snapshot map[string][]byte // original on-disk contents as of Begin; nil means the file didn't exist
+	pending  map[string][]byte // buffered writes, flushed on Commit
+}
+
+// Begin starts a Transaction covering paths, snapshotting each one's
+// current on-disk contents so Rollback can restore them later. A path that
+// doesn't exist yet is recorded as absent.
+func Begin(paths []string) (*Transaction, error) {
+	snapshot := make(map[string][]byte, len(paths))
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("patch: snapshot %s: %w", p, err)
+			}
+			b = nil
+		}
+		snapshot[p] = b
+	}
+	return &Transaction{snapshot: snapshot, pending: map[string][]byte{}}, nil
+}
+
+// Write buffers content for path. Nothing touches disk until Commit.
+func (t *Transaction) Write(path string, content []byte) {
+	t.pending[path] = content
+}
+
+// Read returns path's current in-transaction contents: the most recent
+// buffered Write if one exists, otherwise the Begin-time snapshot.
+func (t *Transaction) Read(path string) []byte {
+	if b, ok := t.pending[path]; ok {
+		return b
+	}
+	return t.snapshot[path]
+}
+
+// Commit flushes every buffered write to disk. If a write fails partway
+// through, Commit rolls back whichever of its own writes already landed
+// before returning the error, so a failed Commit leaves disk exactly as
+// Begin found it.
+func (t *Transaction) Commit() error {
+	written := make([]string, 0, len(t.pending))
+	for path, content := range t.pending {
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.restore(written)
+			return fmt.Errorf("patch: commit %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return nil
+}
+
+// Rollback restores every snapshotted path to its Begin-time contents,
+// removing paths that didn't exist yet. It's safe to call before Commit
+// (where it's a no-op, since nothing has reached disk) or after a failed
+// Commit.
+func (t *Transaction) Rollback() error {
+	paths := make([]string, 0, len(t.snapshot))
+	for p := range t.snapshot {
+		paths = append(paths, p)
+	}
+	return t.restore(paths)
+}
+
+func (t *Transaction) restore(paths []string) error {
+	for _, p := range paths {
+		orig, ok := t.snapshot[p]
+		if !ok {
+			continue
+		}
+		if orig == nil {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("patch: rollback remove %s: %w", p, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(p, orig, 0o644); err != nil {
+			return fmt.Errorf("patch: rollback %s: %w", p, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package patch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestTransactionBuffersUntilCommit(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(a, []byte("package a\n"), 0o644); err != nil {
+		t.Fatalf("seed %s: %v", a, err)
+	}
+
+	txn, err := patch.Begin([]string{a})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	txn.Write(a, []byte("package a // patched\n"))
+
+	if got, _ := os.ReadFile(a); string(got) != "package a\n" {
+		t.Fatalf("disk changed before Commit: %q", got)
+	}
+
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if got, _ := os.ReadFile(a); string(got) != "package a // patched\n" {
+		t.Fatalf("Commit didn't flush the buffered write: %q", got)
+	}
+}
+
+func TestTransactionRollbackRestoresSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	if err := os.WriteFile(a, []byte("package a\n"), 0o644); err != nil {
+		t.Fatalf("seed %s: %v", a, err)
+	}
+	if err := os.WriteFile(b, []byte("package a\n\nvar B int\n"), 0o644); err != nil {
+		t.Fatalf("seed %s: %v", b, err)
+	}
+
+	txn, err := patch.Begin([]string{a, b})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	txn.Write(a, []byte("package a // would-be fix for a\n"))
+	// b's fix fails before it's ever staged - simulating ProposeUnusedFix
+	// erroring out on the second file of a multi-file package.
+
+	if err := txn.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	gotA, _ := os.ReadFile(a)
+	if string(gotA) != "package a\n" {
+		t.Errorf("a.go was left modified after Rollback: %q", gotA)
+	}
+	gotB, _ := os.ReadFile(b)
+	if string(gotB) != "package a\n\nvar B int\n" {
+		t.Errorf("b.go was left modified after Rollback: %q", gotB)
+	}
+}
+
+func TestTransactionRollbackRemovesFilesThatDidntExist(t *testing.T) {
+	dir := t.TempDir()
+	newFile := filepath.Join(dir, "new.go")
+
+	txn, err := patch.Begin([]string{newFile})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	txn.Write(newFile, []byte("package a\n"))
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if _, err := os.Stat(newFile); err != nil {
+		t.Fatalf("Commit didn't create %s: %v", newFile, err)
+	}
+
+	if err := txn.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if _, err := os.Stat(newFile); !os.IsNotExist(err) {
+		t.Errorf("Rollback left %s behind, want it removed: err=%v", newFile, err)
+	}
+}
+
+func TestTransactionCommitRollsBackOnPartialFailure(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	if err := os.WriteFile(a, []byte("package a\n"), 0o644); err != nil {
+		t.Fatalf("seed %s: %v", a, err)
+	}
+	if err := os.WriteFile(b, []byte("package a\n\nvar B int\n"), 0o644); err != nil {
+		t.Fatalf("seed %s: %v", b, err)
+	}
+
+	txn, err := patch.Begin([]string{a, b})
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	txn.Write(a, []byte("package a // patched\n"))
+	txn.Write(b, []byte("package a // patched\n"))
+
+	// Replace b with a directory after Begin snapshotted it, so Commit's
+	// os.WriteFile to b fails partway through - whichever of a or b
+	// Commit reaches first, it must roll back anything it already wrote.
+	if err := os.Remove(b); err != nil {
+		t.Fatalf("remove %s: %v", b, err)
+	}
+	if err := os.Mkdir(b, 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", b, err)
+	}
+
+	if err := txn.Commit(); err == nil {
+		t.Fatal("expected Commit to fail writing over a directory")
+	}
+
+	got, _ := os.ReadFile(a)
+	if string(got) != "package a\n" {
+		t.Errorf("a.go stayed patched after Commit rolled back: %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package patch
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// VerifyError reports that a patched directory still fails to build.
+// Diagnostics holds the raw `go build` output, one line per entry, so a
+// caller can classify them the same way gofix.ParseDiagnostics would.
+type VerifyError struct {
+	Dir         string
+	Diagnostics []string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("patch: %s still fails to build after patching:\n%s", e.Dir, strings.Join(e.Diagnostics, "\n"))
+}
+
+// Verify runs `go build ./...` inside dir and reports a *VerifyError
+// listing the remaining diagnostics if it fails. It's meant to run after a
+// patch pass commits, as a feedback loop confirming the fix actually
+// resolved what it set out to fix rather than leaving (or introducing)
+// other compile errors.
+func Verify(dir string) error {
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimRight(string(out), "\n")
+	var diagnostics []string
+	if trimmed != "" {
+		diagnostics = strings.Split(trimmed, "\n")
+	}
+	return &VerifyError{Dir: dir, Diagnostics: diagnostics}
+}
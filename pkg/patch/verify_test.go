@@ -0,0 +1,46 @@
+package patch_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+func TestVerifyPassesOnBuildablePackage(t *testing.T) {
+	dir := t.TempDir()
+	writeVerifyFixture(t, dir, "package main\n\nfunc main() {}\n")
+
+	if err := patch.Verify(dir); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestVerifyReportsRemainingDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	writeVerifyFixture(t, dir, "package main\n\nfunc main() {\n\tundefinedHelper()\n}\n")
+
+	err := patch.Verify(dir)
+	if err == nil {
+		t.Fatal("expected Verify to report the undefined reference")
+	}
+	var verifyErr *patch.VerifyError
+	if !errors.As(err, &verifyErr) {
+		t.Fatalf("expected a *patch.VerifyError, got %T: %v", err, err)
+	}
+	if len(verifyErr.Diagnostics) == 0 {
+		t.Fatal("expected at least one diagnostic line")
+	}
+}
+
+func writeVerifyFixture(t *testing.T, dir, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module verifyfixture\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("write main.go: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package progress_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+	"github.com/FernandoTN/repo-patcher/pkg/progress"
+)
+
+func TestTerminalReporterNonTTYLineCountMatchesFileCount(t *testing.T) {
+	var buf bytes.Buffer
+	r := progress.NewTerminalReporter(&buf)
+
+	files := []string{"a.go", "b.go", "c.go"}
+	r.Start(len(files))
+	for _, f := range files {
+		r.FileStarted(f)
+		r.FileDone(f, patch.Result{Kind: patch.KindRemoveImport, BeforeStart: 1, BeforeEnd: 2, AfterStart: 1, AfterEnd: 1})
+	}
+	r.Done()
+
+	out := strings.TrimRight(buf.String(), "\n")
+	lines := strings.Split(out, "\n")
+	if len(lines) != len(files) {
+		t.Fatalf("got %d lines, want %d (one per finished file):\n%s", len(lines), len(files), buf.String())
+	}
+	for i, f := range files {
+		if !strings.Contains(lines[i], f) {
+			t.Errorf("line %d = %q, want it to mention %q", i, lines[i], f)
+		}
+	}
+}
+
+func TestNullReporterDoesNothing(t *testing.T) {
+	var r progress.NullReporter
+	r.Start(5)
+	r.FileStarted("a.go")
+	r.FileDone("a.go", patch.Result{})
+	r.Done()
+}
@@ -0,0 +1,171 @@
+// Package sarif converts repo-patcher's patch.Result output into a SARIF
+// 2.1.0 JSON document, the format GitHub Code Scanning expects for
+// uploaded third-party analysis results.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+const (
+	schemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	version   = "2.1.0"
+	toolName  = "repo-patcher"
+)
+
+// Log is the root of a SARIF 2.1.0 document.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a single analysis run: repo-patcher always reports exactly one.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool identifies repo-patcher and the rules (Kinds) it can report.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is SARIF's name for the tool that produced a Run.
+type Driver struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule describes one patch.Kind that can appear as a Result's RuleID.
+type Rule struct {
+	ID string `json:"id"`
+}
+
+// Result is one reported fix, keyed by the patch.Kind that produced it.
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+	Fixes     []Fix      `json:"fixes,omitempty"`
+}
+
+// Message is SARIF's wrapper for a plain-text description.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location points a Result at the file and line range it changed.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is SARIF's file+region pair.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+// ArtifactLocation identifies a file by URI, relative to the analysis root.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a 1-based line range within an ArtifactLocation.
+type Region struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// Fix is SARIF's wrapper for a proposed remediation. ArtifactChanges holds
+// exactly one entry: the unified diff repo-patcher already produced for
+// the edit, carried as its inserted content rather than broken back down
+// into per-line replacements, since UnifiedDiff's output is the artifact
+// a reviewer or `git apply` actually wants.
+type Fix struct {
+	Description     Message          `json:"description"`
+	ArtifactChanges []ArtifactChange `json:"artifactChanges"`
+}
+
+// ArtifactChange pairs a file with the replacement text proposed for it.
+type ArtifactChange struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Replacements     []Replacement    `json:"replacements"`
+}
+
+// Replacement is SARIF's smallest unit of proposed text change.
+type Replacement struct {
+	DeletedRegion   Region          `json:"deletedRegion"`
+	InsertedContent InsertedContent `json:"insertedContent"`
+}
+
+// InsertedContent holds the replacement text for a Replacement.
+type InsertedContent struct {
+	Text string `json:"text"`
+}
+
+// SARIFReport builds a SARIF 2.1.0 document from results. Results with
+// Changed false are considered-but-declined diagnostics, not fixes, and
+// are omitted; every remaining Result maps to one SARIF result, with its
+// patch.Kind as the ruleId and, when DiffOutput was populated (dry-run
+// mode), a fixes entry carrying that unified diff.
+func SARIFReport(results []patch.Result) ([]byte, error) {
+	rules := map[string]bool{}
+	var sarifResults []Result
+	for _, r := range results {
+		if !r.Changed {
+			continue
+		}
+		ruleID := r.Kind.String()
+		rules[ruleID] = true
+
+		sr := Result{
+			RuleID:  ruleID,
+			Message: Message{Text: fmt.Sprintf("repo-patcher applied a %s fix to %s", ruleID, r.File)},
+			Locations: []Location{{
+				PhysicalLocation: PhysicalLocation{
+					ArtifactLocation: ArtifactLocation{URI: r.File},
+					Region:           Region{StartLine: r.AfterStart, EndLine: r.AfterEnd},
+				},
+			}},
+		}
+		if r.DiffOutput != "" {
+			sr.Fixes = []Fix{{
+				Description: Message{Text: fmt.Sprintf("apply the %s fix", ruleID)},
+				ArtifactChanges: []ArtifactChange{{
+					ArtifactLocation: ArtifactLocation{URI: r.File},
+					Replacements: []Replacement{{
+						DeletedRegion:   Region{StartLine: r.BeforeStart, EndLine: r.BeforeEnd},
+						InsertedContent: InsertedContent{Text: r.DiffOutput},
+					}},
+				}},
+			}}
+		}
+		sarifResults = append(sarifResults, sr)
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for id := range rules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	driverRules := make([]Rule, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		driverRules = append(driverRules, Rule{ID: id})
+	}
+
+	log := Log{
+		Schema:  schemaURI,
+		Version: version,
+		Runs: []Run{{
+			Tool:    Tool{Driver: Driver{Name: toolName, Rules: driverRules}},
+			Results: sarifResults,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
@@ -0,0 +1,99 @@
+package sarif_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+	"github.com/FernandoTN/repo-patcher/pkg/sarif"
+)
+
+func TestSARIFReportWellFormed(t *testing.T) {
+	results := []patch.Result{
+		{
+			File: "greet.go", Kind: patch.KindAddImport, Changed: true,
+			BeforeStart: 3, BeforeEnd: 5, AfterStart: 3, AfterEnd: 6,
+			DiffOutput: "--- a/greet.go\n+++ b/greet.go\n@@ -3,3 +3,4 @@\n+\t\"fmt\"\n",
+		},
+		{
+			// A declined fix (Changed false) must not appear in the
+			// report - it was considered, not applied.
+			File: "other.go", Kind: patch.KindBlankUse, Changed: false,
+		},
+	}
+
+	out, err := sarif.SARIFReport(results)
+	if err != nil {
+		t.Fatalf("SARIFReport: %v", err)
+	}
+
+	var log sarif.Log
+	if err := json.Unmarshal(out, &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if log.Schema == "" {
+		t.Error("$schema is empty")
+	}
+	if log.Version != "2.1.0" {
+		t.Errorf("version = %q, want 2.1.0", log.Version)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name == "" {
+		t.Error("tool.driver.name is empty")
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("got %d results, want 1 (the declined fix should be omitted)", len(run.Results))
+	}
+	got := run.Results[0]
+	if got.RuleID != "add-import" {
+		t.Errorf("ruleId = %q, want add-import", got.RuleID)
+	}
+	if len(got.Locations) != 1 {
+		t.Fatalf("got %d locations, want 1", len(got.Locations))
+	}
+	loc := got.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "greet.go" {
+		t.Errorf("artifactLocation.uri = %q, want greet.go", loc.ArtifactLocation.URI)
+	}
+	if loc.Region.StartLine != 3 {
+		t.Errorf("region.startLine = %d, want 3", loc.Region.StartLine)
+	}
+
+	if len(got.Fixes) != 1 {
+		t.Fatalf("got %d fixes, want 1", len(got.Fixes))
+	}
+	changes := got.Fixes[0].ArtifactChanges
+	if len(changes) != 1 || len(changes[0].Replacements) != 1 {
+		t.Fatalf("fix artifactChanges/replacements malformed: %+v", got.Fixes[0])
+	}
+	if diff := changes[0].Replacements[0].InsertedContent.Text; diff != results[0].DiffOutput {
+		t.Errorf("fix diff = %q, want %q", diff, results[0].DiffOutput)
+	}
+}
+
+func TestSARIFReportOmitsFixesWithoutDiffOutput(t *testing.T) {
+	results := []patch.Result{
+		{File: "greet.go", Kind: patch.KindRemoveImport, Changed: true, AfterStart: 1, AfterEnd: 1},
+	}
+
+	out, err := sarif.SARIFReport(results)
+	if err != nil {
+		t.Fatalf("SARIFReport: %v", err)
+	}
+
+	var log sarif.Log
+	if err := json.Unmarshal(out, &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(log.Runs[0].Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(log.Runs[0].Results))
+	}
+	if fixes := log.Runs[0].Results[0].Fixes; fixes != nil {
+		t.Errorf("Fixes = %v, want nil when DiffOutput is empty", fixes)
+	}
+}
@@ -0,0 +1,184 @@
+// Package server exposes gofix's deterministic patch pipeline over HTTP,
+// for editor plugins and dashboards written outside Go to call into: a
+// POST /patch endpoint that simulates a fix in memory (see
+// gofix.SimulateWithResults) and returns the patched content, a
+// GET /scenarios endpoint listing the fixture names under ScenariosDir, and
+// a GET /health liveness check. It uses net/http and encoding/json only -
+// no external router - matching every other entry point into this
+// repository (the CLI, the fixtures suite) staying on the standard
+// library.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/fixtures"
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// Defaults for Options fields left at their zero value.
+const (
+	DefaultMaxRequestBytes = 1 << 20 // 1 MB
+	DefaultRequestTimeout  = 30 * time.Second
+	DefaultScenariosDir    = "scenarios"
+)
+
+// Options configures the handler NewHandler returns.
+type Options struct {
+	// MaxRequestBytes caps a request body's size; a larger body is
+	// rejected with 413 Request Entity Too Large. Zero means
+	// DefaultMaxRequestBytes.
+	MaxRequestBytes int64
+	// RequestTimeout bounds how long a single request may run before it's
+	// aborted with 503 Service Unavailable. Zero means
+	// DefaultRequestTimeout.
+	RequestTimeout time.Duration
+	// ScenariosDir is the scenarios/ directory GET /scenarios lists.
+	// Empty means DefaultScenariosDir.
+	ScenariosDir string
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxRequestBytes == 0 {
+		o.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if o.RequestTimeout == 0 {
+		o.RequestTimeout = DefaultRequestTimeout
+	}
+	if o.ScenariosDir == "" {
+		o.ScenariosDir = DefaultScenariosDir
+	}
+	return o
+}
+
+// PatchRequest is POST /patch's request body.
+type PatchRequest struct {
+	File        string            `json:"file"`
+	Content     string            `json:"content"`
+	Diagnostics []PatchDiagnostic `json:"diagnostics"`
+}
+
+// PatchDiagnostic is one compiler/vet diagnostic in a PatchRequest, the
+// wire shape of gofix.Diagnostic (File is filled in from PatchRequest.File
+// and so isn't part of the JSON).
+type PatchDiagnostic struct {
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Message string `json:"message"`
+}
+
+// PatchResponse is POST /patch's response body.
+type PatchResponse struct {
+	PatchedContent string         `json:"patched_content"`
+	Results        []patch.Result `json:"results"`
+}
+
+// ScenariosResponse is GET /scenarios's response body.
+type ScenariosResponse struct {
+	Scenarios []string `json:"scenarios"`
+}
+
+// HealthResponse is GET /health's response body.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// NewHandler returns the HTTP handler serving /patch, /scenarios, and
+// /health, each request size-limited and time-bounded per opts.
+func NewHandler(opts Options) http.Handler {
+	opts = opts.withDefaults()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", handleHealth)
+	mux.HandleFunc("/scenarios", opts.handleScenarios)
+	mux.HandleFunc("/patch", opts.handlePatch)
+
+	timeoutMsg := fmt.Sprintf(`{"error":"request timed out after %s"}`, opts.RequestTimeout)
+	return http.TimeoutHandler(mux, opts.RequestTimeout, timeoutMsg)
+}
+
+// ListenAndServe starts an HTTP server on addr (e.g. ":8080") serving
+// NewHandler(opts), blocking until the server stops or ctx is canceled.
+func ListenAndServe(ctx context.Context, addr string, opts Options) error {
+	srv := &http.Server{Addr: addr, Handler: NewHandler(opts)}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+func (o Options) handleScenarios(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	scenarios, err := fixtures.Discover(os.DirFS(o.ScenariosDir))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	names := make([]string, len(scenarios))
+	for i, s := range scenarios {
+		names[i] = s.Name
+	}
+	writeJSON(w, http.StatusOK, ScenariosResponse{Scenarios: names})
+}
+
+func (o Options) handlePatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, o.MaxRequestBytes)
+	var req PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.File == "" {
+		http.Error(w, `"file" is required`, http.StatusBadRequest)
+		return
+	}
+
+	diags := make([]gofix.Diagnostic, len(req.Diagnostics))
+	for i, d := range req.Diagnostics {
+		diags[i] = gofix.Diagnostic{File: filepath.Base(req.File), Line: d.Line, Col: d.Col, Message: d.Message}
+	}
+
+	patched, results, err := gofix.SimulateWithResults(req.File, []byte(req.Content), diags)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, http.StatusOK, PatchResponse{PatchedContent: string(patched), Results: results})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
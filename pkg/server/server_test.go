@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FernandoTN/repo-patcher/pkg/server"
+)
+
+func TestHealthScenariosAndPatch(t *testing.T) {
+	ts := httptest.NewServer(server.NewHandler(server.Options{ScenariosDir: "../../scenarios"}))
+	defer ts.Close()
+
+	t.Run("health", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/health")
+		if err != nil {
+			t.Fatalf("GET /health: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /health: status = %d, want 200", resp.StatusCode)
+		}
+		var got server.HealthResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if got.Status != "ok" {
+			t.Errorf("Status = %q, want %q", got.Status, "ok")
+		}
+	})
+
+	t.Run("scenarios", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/scenarios")
+		if err != nil {
+			t.Fatalf("GET /scenarios: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /scenarios: status = %d, want 200", resp.StatusCode)
+		}
+		var got server.ScenariosResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(got.Scenarios) == 0 {
+			t.Error("Scenarios is empty, want at least one discovered scenario")
+		}
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		src := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tx := 1\n\tfmt.Println(\"hi\")\n}\n"
+		reqBody, err := json.Marshal(server.PatchRequest{
+			File:    "main.go",
+			Content: src,
+			Diagnostics: []server.PatchDiagnostic{
+				{Line: 6, Col: 2, Message: "x declared and not used"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+		resp, err := http.Post(ts.URL+"/patch", "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("POST /patch: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST /patch: status = %d, want 200", resp.StatusCode)
+		}
+		var got server.PatchResponse
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !strings.Contains(got.PatchedContent, "_ = x") {
+			t.Errorf("PatchedContent does not blank-assign the unused var:\n%s", got.PatchedContent)
+		}
+		if len(got.Results) == 0 {
+			t.Error("Results is empty, want at least one applied fix")
+		}
+	})
+}
+
+func TestPatchRejectsOversizedBody(t *testing.T) {
+	ts := httptest.NewServer(server.NewHandler(server.Options{MaxRequestBytes: 16}))
+	defer ts.Close()
+
+	reqBody, err := json.Marshal(server.PatchRequest{File: "main.go", Content: "package main\n"})
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	resp, err := http.Post(ts.URL+"/patch", "application/json", bytes.NewReader(reqBody))
+	if err != nil {
+		t.Fatalf("POST /patch: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	ts := httptest.NewServer(server.NewHandler(server.Options{RequestTimeout: time.Nanosecond}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
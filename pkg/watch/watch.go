@@ -0,0 +1,174 @@
+// Package watch re-runs the gofix pipeline on a file the moment it's saved,
+// for a developer who wants live patching as they type rather than running
+// the repo-patcher CLI by hand after every edit.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/FernandoTN/repo-patcher/pkg/gofix"
+	"github.com/FernandoTN/repo-patcher/pkg/patch"
+)
+
+// WatchEvent reports the outcome of re-running the fixer pipeline on one
+// changed file.
+type WatchEvent struct {
+	// File is the path that changed, relative to the Watcher's Root.
+	File string
+	// Results holds the patch.Results applied to File. Empty means the
+	// pipeline ran and found nothing to fix.
+	Results []patch.Result
+	// Err is set instead of Results when the pipeline failed to run.
+	Err error
+}
+
+// defaultDebounce is how long Watcher waits after a file's last WRITE event
+// before re-running the pipeline on it, so that an editor's multiple writes
+// per save (truncate, then write, then rename-into-place) collapse into a
+// single run.
+const defaultDebounce = 200 * time.Millisecond
+
+// Watcher monitors every .go file under Root and re-runs the gofix unused
+// pass on a file each time it's written, reporting the outcome on Events.
+// The zero value is not usable; construct one with New.
+type Watcher struct {
+	// Root is the directory tree to monitor.
+	Root string
+	// Debounce is how long to wait after a file's last write before
+	// re-running the pipeline on it. Zero means defaultDebounce.
+	Debounce time.Duration
+	// RunOptions configures each pipeline run. Mode defaults to
+	// gofix.UnusedRemove when left zero.
+	RunOptions gofix.RunOptions
+
+	// Events reports the outcome of each debounced re-run. It's closed
+	// once Run returns.
+	Events <-chan WatchEvent
+
+	events chan WatchEvent
+
+	mu      sync.Mutex
+	timers  map[string]*time.Timer
+	pending sync.WaitGroup
+}
+
+// New returns a Watcher over root with Events ready to receive.
+func New(root string) *Watcher {
+	events := make(chan WatchEvent)
+	return &Watcher{
+		Root:   root,
+		Events: events,
+		events: events,
+		timers: map[string]*time.Timer{},
+	}
+}
+
+// Run watches Root until ctx is canceled, at which point it stops
+// outstanding timers, closes Events, and returns ctx.Err(). It blocks for
+// the duration of the watch, so callers typically run it in its own
+// goroutine.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	if err := addDirs(fsw, w.Root); err != nil {
+		return err
+	}
+
+	defer close(w.events)
+	for {
+		select {
+		case <-ctx.Done():
+			w.mu.Lock()
+			for _, t := range w.timers {
+				// Stop reports whether it beat the timer to the punch. If
+				// so, the AfterFunc - and the pending.Done it would have
+				// run - never happens, so we must account for it here
+				// instead, or Wait below would hang on a count that can
+				// now never reach zero.
+				if t.Stop() {
+					w.pending.Done()
+				}
+			}
+			w.mu.Unlock()
+			w.pending.Wait()
+			return ctx.Err()
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op&fsnotify.Write == 0 || !strings.HasSuffix(ev.Name, ".go") {
+				continue
+			}
+			w.debounce(ctx, ev.Name)
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.events <- WatchEvent{Err: err}
+		}
+	}
+}
+
+// debounce (re)schedules file's pipeline run for Debounce from now,
+// canceling any run already scheduled for it.
+func (w *Watcher) debounce(ctx context.Context, file string) {
+	d := w.Debounce
+	if d == 0 {
+		d = defaultDebounce
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.timers[file]; ok && t.Stop() {
+		// See the matching comment in Run: Stop only returns true when it
+		// beat the timer, meaning its pending.Done will never run.
+		w.pending.Done()
+	}
+	w.pending.Add(1)
+	w.timers[file] = time.AfterFunc(d, func() {
+		defer w.pending.Done()
+		w.runOne(ctx, file)
+	})
+}
+
+// runOne re-runs the pipeline on file and publishes the outcome, unless ctx
+// was canceled first.
+func (w *Watcher) runOne(ctx context.Context, file string) {
+	opts := w.RunOptions
+	if opts.Mode == "" {
+		opts.Mode = gofix.UnusedRemove
+	}
+	results, err := gofix.RunUnusedPass([]string{file}, opts)
+
+	select {
+	case <-ctx.Done():
+	case w.events <- WatchEvent{File: file, Results: results, Err: err}:
+	}
+}
+
+// addDirs registers root and every directory beneath it with fsw: fsnotify
+// watches a single directory's entries, not a tree, so a new subdirectory
+// found after Run starts won't be picked up - acceptable for the common
+// case of watching an existing, already-laid-out package tree.
+func addDirs(fsw *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return fsw.Add(path)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatcherReportsFixOnSave writes a Go file with an unused import into a
+// temp directory, starts a Watcher over it, saves the file, and asserts a
+// WatchEvent with a non-empty Results arrives within 2 seconds.
+func TestWatcherReportsFixOnSave(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module watchtest\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile go.mod: %v", err)
+	}
+	file := filepath.Join(dir, "main.go")
+	const initial = `package main
+
+func main() {}
+`
+	if err := os.WriteFile(file, []byte(initial), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go: %v", err)
+	}
+
+	w := New(dir)
+	w.Debounce = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() { runErr <- w.Run(ctx) }()
+
+	// Give fsnotify a moment to register the watch before the write it
+	// needs to see.
+	time.Sleep(50 * time.Millisecond)
+
+	const withUnusedImport = `package main
+
+import "strings"
+
+func main() {}
+`
+	if err := os.WriteFile(file, []byte(withUnusedImport), 0o644); err != nil {
+		t.Fatalf("WriteFile (save): %v", err)
+	}
+
+	select {
+	case ev := <-w.Events:
+		if ev.Err != nil {
+			t.Fatalf("WatchEvent.Err = %v", ev.Err)
+		}
+		if len(ev.Results) == 0 {
+			t.Fatalf("WatchEvent.Results is empty, want the unused import fix")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a WatchEvent")
+	}
+
+	cancel()
+	select {
+	case err := <-runErr:
+		if err != context.Canceled {
+			t.Errorf("Run returned %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
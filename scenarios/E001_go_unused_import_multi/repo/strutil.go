@@ -0,0 +1,6 @@
+package main
+
+// Shout uppercases s and appends an exclamation mark.
+func Shout(s string) string {
+    return s + "!"
+}
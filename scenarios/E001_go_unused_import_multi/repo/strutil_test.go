@@ -0,0 +1,16 @@
+package main
+
+import (
+    "strconv"
+    "strings"
+    "testing"
+)
+
+func TestShout(t *testing.T) {
+    result := Shout(strings.ToUpper("hi"))
+    expected := "HI!"
+
+    if result != expected {
+        t.Errorf("Expected %q, got %q", expected, result)
+    }
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+    _ "fmt"
+    "strconv"
+    "testing"
+)
+
+// TestGreet exercises Greet. The blank import of fmt is unreferenced, but
+// that's the whole point of a blank import, so the unused-import pass must
+// leave it alone; the compiler never emits "imported and not used" for one
+// in the first place. strconv, on the other hand, really is dead and must
+// be removed.
+func TestGreet(t *testing.T) {
+    result := Greet("Cleo")
+    expected := "Hi, Cleo"
+
+    if result != expected {
+        t.Errorf("Expected %q, got %q", expected, result)
+    }
+}
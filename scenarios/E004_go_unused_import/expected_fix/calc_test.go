@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	result := Add(2, 3)
+	expected := 5
+
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
+
+func TestSub(t *testing.T) {
+	result := Sub(5, 3)
+	expected := 2
+
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
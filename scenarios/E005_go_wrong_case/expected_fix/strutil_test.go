@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	result := Describe(42)
+	expected := "The answer is 42"
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
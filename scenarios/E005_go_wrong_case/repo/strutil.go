@@ -0,0 +1,17 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+)
+
+// Describe converts n to a string and embeds it in a sentence.
+// This calls strconv.itoa instead of strconv.Itoa - wrong case causes
+// "undefined: strconv.itoa" even though strconv is already imported.
+func Describe(n int) string {
+    return "The answer is " + strconv.itoa(n)
+}
+
+func main() {
+    fmt.Println(Describe(42))
+}
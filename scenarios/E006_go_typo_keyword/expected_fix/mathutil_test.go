@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestSquare(t *testing.T) {
+	result := Square(4)
+	expected := 16
+
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+// Square returns n squared.
+// "retrun" is a typo for "return" - a syntax error the compiler reports as
+// "syntax error: unexpected name retrun".
+func Square(n int) int {
+    retrun n * n
+}
+
+func main() {
+    fmt.Println(Square(4))
+}
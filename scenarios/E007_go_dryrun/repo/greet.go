@@ -0,0 +1,6 @@
+package main
+
+// Greet returns a friendly greeting for name.
+func Greet(name string) string {
+	return "Hi, " + name
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGreet(t *testing.T) {
+	result := Greet("Ada")
+	expected := "Hi, Ada"
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
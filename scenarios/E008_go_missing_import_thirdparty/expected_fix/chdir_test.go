@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func TestSafeChdir(t *testing.T) {
+	if err := SafeChdir("."); err != nil {
+		t.Errorf("SafeChdir(\".\") = %v, want nil", err)
+	}
+
+	if err := SafeChdir("/does/not/exist"); err == nil {
+		t.Error("SafeChdir on a missing dir = nil, want an error")
+	}
+}
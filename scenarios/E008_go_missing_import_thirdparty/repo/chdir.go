@@ -0,0 +1,12 @@
+package main
+
+import "os"
+
+// SafeChdir changes into dir, wrapping any failure with context. It uses
+// errs.Wrap but doesn't import errs - causing undefined error.
+func SafeChdir(dir string) error {
+	if err := os.Chdir(dir); err != nil {
+		return errs.Wrap(err, "chdir failed")
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+// Package errs stands in for a third-party error-wrapping package (like
+// github.com/pkg/errors) so this fixture can exercise ModuleResolver
+// without depending on the network.
+package errs
+
+import "fmt"
+
+// Wrap annotates err with message, returning nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
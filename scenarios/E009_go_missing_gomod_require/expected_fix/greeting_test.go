@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func TestGreeting(t *testing.T) {
+	if got, want := Greeting("World"), "Hello, World!"; got != want {
+		t.Errorf("Greeting(%q) = %q, want %q", "World", got, want)
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+// Greeting returns a friendly greeting for name. It uses greeter.Hello but
+// doesn't import greeter - causing undefined error.
+func Greeting(name string) string {
+	return greeter.Hello(name)
+}
@@ -0,0 +1,8 @@
+// Package greeter stands in for a third-party greeting package so this
+// fixture can exercise GoModPatcher without depending on the network.
+package greeter
+
+// Hello returns a greeting for name.
+func Hello(name string) string {
+	return "Hello, " + name + "!"
+}
@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+// Describe embeds n's value in a sentence, but assigns the int straight to
+// the string variable instead of converting it first - "cannot use n
+// (variable of type int) as string value in assignment".
+func Describe(n int) string {
+	var s string
+	s = n
+	return "The answer is " + s
+}
+
+func main() {
+	fmt.Println(Describe(42))
+}
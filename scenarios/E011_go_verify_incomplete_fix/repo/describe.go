@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Describe reports n and its double. strconv is imported but never used -
+// gofix's unused-import pass can remove that cleanly - but the call to
+// doubled below names a function nobody ever wrote, which no deterministic
+// fixer can invent. Even after the unused import is gone, `go build` still
+// fails with "undefined: doubled", which --verify is expected to catch.
+func Describe(n int) string {
+	return fmt.Sprintf("value: %d, doubled: %d", n, doubled(n))
+}
+
+func main() {
+	fmt.Println(Describe(21))
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseThree parses three decimal strings into ints, but discards
+// strconv.Atoi's error at every call site - "assignment mismatch: 1
+// variable but strconv.Atoi returns 2 values".
+func ParseThree(a, b, c string) (int, int, int, error) {
+	x := strconv.Atoi(a)
+	y := strconv.Atoi(b)
+	z := strconv.Atoi(c)
+	return x, y, z, nil
+}
+
+func main() {
+	x, y, z, err := ParseThree("1", "2", "3")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(x, y, z)
+}
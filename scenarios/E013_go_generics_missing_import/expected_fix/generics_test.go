@@ -0,0 +1,15 @@
+package main
+
+import "testing"
+
+// TestMax exercises Max with both an int and a string instantiation,
+// checking that fixing the missing "cmp" import didn't corrupt the
+// function's type parameter list ([T cmp.Ordered]) along the way.
+func TestMax(t *testing.T) {
+	if got := Max(3, 5); got != 5 {
+		t.Errorf("Max(3, 5) = %d, want 5", got)
+	}
+	if got := Max("pear", "apple"); got != "pear" {
+		t.Errorf("Max(\"pear\", \"apple\") = %q, want %q", got, "pear")
+	}
+}
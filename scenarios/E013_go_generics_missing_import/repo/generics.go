@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+// Max returns the larger of a and b. It uses cmp.Ordered as T's
+// constraint but never imports "cmp" - "undefined: cmp".
+func Max[T cmp.Ordered](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func main() {
+	fmt.Println(Max(3, 5))
+}
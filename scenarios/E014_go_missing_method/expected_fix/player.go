@@ -0,0 +1,35 @@
+package main
+
+import "fmt"
+
+// Profile is satisfied by anything that can describe and score a player.
+type Profile interface {
+	Name() string
+	Score() (int, error)
+}
+
+// Player has neither Profile method yet - "Player does not implement
+// Profile (missing method Name)".
+type Player struct {
+	FirstName string
+}
+
+func describe(p Profile) string {
+	return fmt.Sprintf("%s scores", p.Name())
+}
+
+func main() {
+	p := Player{FirstName: "Ada"}
+	fmt.Println(describe(p))
+}
+
+func (p Player) Name() string {
+	var ret0 string
+	return ret0
+}
+
+func (p Player) Score() (int, error) {
+	var ret0 int
+	var ret1 error
+	return ret0, ret1
+}
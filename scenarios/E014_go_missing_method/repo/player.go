@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+// Profile is satisfied by anything that can describe and score a player.
+type Profile interface {
+	Name() string
+	Score() (int, error)
+}
+
+// Player has neither Profile method yet - "Player does not implement
+// Profile (missing method Name)".
+type Player struct {
+	FirstName string
+}
+
+func describe(p Profile) string {
+	return fmt.Sprintf("%s scores", p.Name())
+}
+
+func main() {
+	p := Player{FirstName: "Ada"}
+	fmt.Println(describe(p))
+}
@@ -0,0 +1,34 @@
+package main
+
+import "fmt"
+
+// run has a three-level err shadowing chain: the outer err from step1,
+// a multi-name `val, err := step2()` that shadows it (go vet's shadow
+// analyzer flags this one - "declaration of \"err\" shadows declaration at
+// run.go:11:2"), and a third, independent err inside the `val > 0` block
+// that shadows the second.
+func run() error {
+	err := step1()
+	if err != nil {
+		return err
+	}
+	val, err1 := step2()
+	if err1 != nil {
+		return err1
+	}
+	if val > 0 {
+		err := step3()
+		if err != nil {
+			return err
+		}
+	}
+	return err1
+}
+
+func step1() error        { return nil }
+func step2() (int, error) { return 0, nil }
+func step3() error        { return nil }
+
+func main() {
+	fmt.Println(run())
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// loadConfig has five distinct io/ioutil usages DeprecatedAPIFixer should
+// migrate to their Go 1.16+ os/io equivalents: ReadFile, WriteFile,
+// ReadAll, TempDir, and Discard.
+func loadConfig(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(path+".bak", data, 0o644); err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(io.Reader(nil), 0))
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, body...)
+
+	dir, err := ioutil.TempDir("", "config-*")
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("scratch dir:", dir)
+
+	io.Copy(ioutil.Discard, io.Reader(nil))
+
+	return data, nil
+}
+
+func main() {
+	if _, err := loadConfig("config.json"); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// lookup has two distinct out-of-range index accesses BoundsCheckFixer
+// should guard against primes, which is fixed at length 3 by its
+// composite literal - plus one access already protected by an enclosing
+// len(primes) check, which must be left untouched.
+func lookup() (int, error) {
+	primes := []int{2, 3, 5}
+
+	if len(primes) > 2 {
+		guarded := primes[2]
+		fmt.Println("third prime:", guarded)
+	}
+	if len(primes) <= 5 {
+		return 0, ErrOutOfBounds
+	}
+
+	a := primes[5]
+	if len(primes) <= 7 {
+		return 0, ErrOutOfBounds
+	}
+	b := primes[7]
+	return a + b, nil
+}
+
+func main() {
+	n, err := lookup()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(n)
+}
+
+var ErrOutOfBounds = errors.New("gofix: index out of bounds")
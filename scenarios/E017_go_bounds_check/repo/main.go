@@ -0,0 +1,29 @@
+package main
+
+import "fmt"
+
+// lookup has two distinct out-of-range index accesses BoundsCheckFixer
+// should guard against primes, which is fixed at length 3 by its
+// composite literal - plus one access already protected by an enclosing
+// len(primes) check, which must be left untouched.
+func lookup() (int, error) {
+	primes := []int{2, 3, 5}
+
+	if len(primes) > 2 {
+		guarded := primes[2]
+		fmt.Println("third prime:", guarded)
+	}
+
+	a := primes[5]
+	b := primes[7]
+	return a + b, nil
+}
+
+func main() {
+	n, err := lookup()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(n)
+}
@@ -0,0 +1,7 @@
+package main
+
+import "fmt"
+
+func jsonAPIVersion() string {
+	return fmt.Sprintf("v1-%d-%s", userID, serverIP)
+}
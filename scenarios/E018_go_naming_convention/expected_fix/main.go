@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+var userID = 42
+
+var serverIP = "127.0.0.1"
+
+const configID = "cfg-001"
+
+func parseHTMLBody(body string) string {
+	return body
+}
+
+func main() {
+	fmt.Println(jsonAPIVersion(), serverIP, configID, parseHTMLBody("<html></html>"), userID)
+}
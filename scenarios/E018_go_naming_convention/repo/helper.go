@@ -0,0 +1,7 @@
+package main
+
+import "fmt"
+
+func jsonApiVersion() string {
+	return fmt.Sprintf("v1-%d-%s", userId, serverIp)
+}
@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+var userId = 42
+
+var serverIp = "127.0.0.1"
+
+const configId = "cfg-001"
+
+func parseHtmlBody(body string) string {
+	return body
+}
+
+func main() {
+	fmt.Println(jsonApiVersion(), serverIp, configId, parseHtmlBody("<html></html>"), userId)
+}
@@ -0,0 +1,7 @@
+package main
+
+import "context"
+
+func handleRequest(ctx context.Context, id string) (string, error) {
+	return loadUser(ctx, id)
+}
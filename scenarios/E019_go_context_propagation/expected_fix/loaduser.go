@@ -0,0 +1,7 @@
+package main
+
+import "context"
+
+func loadUser(ctx context.Context, id string) (string, error) {
+	return Fetch(ctx, id)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func Fetch(ctx context.Context, id string) (string, error) {
+	return "record-" + id, nil
+}
+
+func main() {
+	ctx := context.Background()
+	record, err := Fetch(ctx, "42")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(record)
+}
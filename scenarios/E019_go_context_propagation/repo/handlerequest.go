@@ -0,0 +1,5 @@
+package main
+
+func handleRequest(id string) (string, error) {
+	return loadUser(id)
+}
@@ -0,0 +1,5 @@
+package main
+
+func loadUser(id string) (string, error) {
+	return Fetch(id)
+}
@@ -0,0 +1,8 @@
+package account
+
+import (
+	"example.com/cycles/shared"
+)
+
+// Summary reports the account's current billing rate.
+func Summary() string { return "summary: " + shared.Rate() }
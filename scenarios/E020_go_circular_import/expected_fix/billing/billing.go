@@ -0,0 +1,8 @@
+package billing
+
+import (
+	"example.com/cycles/shared"
+)
+
+// Describe names the account a billing rate belongs to.
+func Describe() string { return "bill for " + shared.Name() }
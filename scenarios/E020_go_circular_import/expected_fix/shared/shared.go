@@ -0,0 +1,7 @@
+package shared
+
+// Name is the account holder's display name.
+func Name() string { return "acct" }
+
+// Rate is the flat per-period billing rate.
+func Rate() string { return "10.00" }
@@ -0,0 +1,9 @@
+package account
+
+import "example.com/cycles/billing"
+
+// Name is the account holder's display name.
+func Name() string { return "acct" }
+
+// Summary reports the account's current billing rate.
+func Summary() string { return "summary: " + billing.Rate() }
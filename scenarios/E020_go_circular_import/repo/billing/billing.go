@@ -0,0 +1,9 @@
+package billing
+
+import "example.com/cycles/account"
+
+// Rate is the flat per-period billing rate.
+func Rate() string { return "10.00" }
+
+// Describe names the account a billing rate belongs to.
+func Describe() string { return "bill for " + account.Name() }
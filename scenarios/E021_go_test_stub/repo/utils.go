@@ -0,0 +1,11 @@
+package main
+
+// FormatMessage formats a message with the given name and age.
+func FormatMessage(name string, age int) string {
+	return "Hello " + name
+}
+
+// GetGreeting returns a simple greeting.
+func GetGreeting(name string) string {
+	return "Hello " + name
+}
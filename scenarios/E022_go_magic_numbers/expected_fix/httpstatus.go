@@ -0,0 +1,59 @@
+package main
+
+import "fmt"
+
+// describeStatus returns a short label for an HTTP status code.
+func describeStatus(status int) string {
+	if status == statusValue404 {
+		return "not found"
+	}
+	if status == statusValue500 {
+		return "server error"
+	}
+	return fmt.Sprintf("status %d", status)
+}
+
+// isDefaultPort reports whether port is the service's default port.
+func isDefaultPort(port int) bool {
+	return port == portValue8080
+}
+
+// usesDefaultPort is a second caller comparing against the same default
+// port, so the literal repeats as well as appearing in a comparison.
+func usesDefaultPort(port int) bool {
+	return port == portValue8080
+}
+
+// retryBackoff returns the delay, in seconds, before the nth retry.
+func retryBackoff(attempt int) float64 {
+	base := magicNumber4
+	return base * float64(attempt)
+}
+
+// coldStartTimeout is how long, in seconds, a cold worker gets to report
+// ready before it's killed and restarted - the same delay retryBackoff
+// uses for its base, so it repeats rather than being compared.
+func coldStartTimeout() float64 {
+	return magicNumber4
+}
+
+// newPool caps how many connections a single pool may hold.
+func newPool() int {
+	return currentValue100
+}
+
+// growPool compares the pool's current size against the same cap.
+func growPool(current int) int {
+	if current >= currentValue100 {
+		return current
+	}
+	return current + 1
+}
+
+const (
+	statusValue404  = 404
+	statusValue500  = 500
+	portValue8080   = 8080
+	magicNumber4    = 1.5
+	currentValue100 = 100
+)